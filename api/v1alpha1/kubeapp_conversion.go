@@ -0,0 +1,690 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v1beta1 "github.com/k8s/kube-app-operator/api/v1beta1"
+)
+
+// v1alpha1 keeps its original single-port Service and single-route Ingress fields next to
+// the ports and rules lists of v1beta1. A spec that does not convert back exactly in either
+// direction is kept as JSON in the ConversionDataAnnotation of the converted object: a
+// v1alpha1 object carries the full v1beta1 spec and a v1beta1 object carries the v1alpha1
+// spec it was written with. The saved spec is restored on the way back as long as the
+// converted spec was not edited.
+
+// ConvertTo converts this KubeApp (v1alpha1) to the Hub version (v1beta1).
+func (src *KubeApp) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v1beta1.KubeApp)
+	if !ok {
+		return fmt.Errorf("expected a v1beta1 KubeApp but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = convertSpecToV1beta1(src.Spec.DeepCopy())
+	dst.Status = convertStatusToV1beta1(src.Status.DeepCopy())
+
+	if data, ok := popConversionData(&dst.ObjectMeta); ok {
+		var saved v1beta1.KubeAppSpec
+		if err := json.Unmarshal([]byte(data), &saved); err != nil {
+			return fmt.Errorf("invalid %s annotation: %w", ConversionDataAnnotation, err)
+		}
+		// Nothing was edited since the down-conversion: the saved spec is exact. Otherwise the
+		// v1alpha1 spec is authoritative.
+		if equality.Semantic.DeepEqual(convertSpecFromV1beta1(saved.DeepCopy()), src.Spec) {
+			dst.Spec = saved
+			return nil
+		}
+	}
+
+	if equality.Semantic.DeepEqual(convertSpecFromV1beta1(dst.Spec.DeepCopy()), src.Spec) {
+		return nil
+	}
+	return setConversionData(&dst.ObjectMeta, src.Spec)
+}
+
+// ConvertFrom converts the Hub version (v1beta1) to this version (v1alpha1).
+func (dst *KubeApp) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v1beta1.KubeApp)
+	if !ok {
+		return fmt.Errorf("expected a v1beta1 KubeApp but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	dst.Spec = convertSpecFromV1beta1(src.Spec.DeepCopy())
+	dst.Status = convertStatusFromV1beta1(src.Status.DeepCopy())
+
+	if data, ok := popConversionData(&dst.ObjectMeta); ok {
+		var saved KubeAppSpec
+		if err := json.Unmarshal([]byte(data), &saved); err != nil {
+			return fmt.Errorf("invalid %s annotation: %w", ConversionDataAnnotation, err)
+		}
+		// The object was written as v1alpha1 and not edited since: give back the exact
+		// v1alpha1 spec, including its single-port and single-route fields.
+		if equality.Semantic.DeepEqual(convertSpecToV1beta1(saved.DeepCopy()), src.Spec) {
+			dst.Spec = saved
+			return nil
+		}
+	}
+
+	if equality.Semantic.DeepEqual(convertSpecToV1beta1(dst.Spec.DeepCopy()), src.Spec) {
+		return nil
+	}
+	return setConversionData(&dst.ObjectMeta, src.Spec)
+}
+
+// popConversionData removes the ConversionDataAnnotation from meta and returns its value.
+func popConversionData(meta *metav1.ObjectMeta) (string, bool) {
+	data, ok := meta.Annotations[ConversionDataAnnotation]
+	if !ok {
+		return "", false
+	}
+	delete(meta.Annotations, ConversionDataAnnotation)
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+	return data, true
+}
+
+// setConversionData stores spec as JSON in the ConversionDataAnnotation of meta.
+func setConversionData(meta *metav1.ObjectMeta, spec any) error {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return err
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[ConversionDataAnnotation] = string(data)
+	return nil
+}
+
+func convertSpecToV1beta1(in *KubeAppSpec) v1beta1.KubeAppSpec {
+	out := v1beta1.KubeAppSpec{
+		EnableDeployment: in.EnableDeployment,
+		EnableService:    in.EnableService,
+		EnableIngress:    in.EnableIngress,
+		EnablePvc:        in.EnablePvc,
+		WorkloadType:     v1beta1.WorkloadType(in.WorkloadType),
+	}
+
+	if d := in.Deployment; d != nil {
+		out.Deployment = &v1beta1.DeploymentSpec{
+			Name:                          d.Name,
+			Image:                         d.Image,
+			Replicas:                      d.Replicas,
+			Resources:                     d.Resources,
+			Ports:                         d.Ports,
+			LivenessProbe:                 d.LivenessProbe,
+			ReadinessProbe:                d.ReadinessProbe,
+			Lifecycle:                     d.Lifecycle,
+			NodeSelector:                  d.NodeSelector,
+			Tolerations:                   d.Tolerations,
+			TerminationGracePeriodSeconds: d.TerminationGracePeriodSeconds,
+			ImagePullSecrets:              d.ImagePullSecrets,
+			DNSConfig:                     d.DNSConfig,
+			Affinity:                      d.Affinity,
+			Env:                           d.Env,
+		}
+		for _, v := range d.Volumes {
+			out.Deployment.Volumes = append(out.Deployment.Volumes, v1beta1.VolumeConfig(v))
+		}
+		for _, vm := range d.VolumeMounts {
+			out.Deployment.VolumeMounts = append(out.Deployment.VolumeMounts, v1beta1.VolumeMount(vm))
+		}
+		out.Deployment.InitContainers = convertContainersToV1beta1(d.InitContainers)
+		out.Deployment.Sidecars = convertContainersToV1beta1(d.Sidecars)
+		if d.Strategy != nil {
+			strategy := v1beta1.DeploymentStrategySpec(*d.Strategy)
+			out.Deployment.Strategy = &strategy
+		}
+		if d.SecurityProfile != nil {
+			profile := v1beta1.SecurityProfileSpec{
+				Type:                     v1beta1.SecurityProfileType(d.SecurityProfile.Type),
+				PodSecurityContext:       d.SecurityProfile.PodSecurityContext,
+				ContainerSecurityContext: d.SecurityProfile.ContainerSecurityContext,
+			}
+			out.Deployment.SecurityProfile = &profile
+		}
+	}
+
+	if sts := in.StatefulSet; sts != nil {
+		out.StatefulSet = &v1beta1.StatefulSetSpec{
+			ServiceName:         sts.ServiceName,
+			PodManagementPolicy: sts.PodManagementPolicy,
+		}
+		for _, t := range sts.VolumeClaimTemplates {
+			out.StatefulSet.VolumeClaimTemplates = append(out.StatefulSet.VolumeClaimTemplates, v1beta1.VolumeClaimTemplate(t))
+		}
+	}
+
+	if in.DaemonSet != nil {
+		daemonSet := v1beta1.DaemonSetSpec(*in.DaemonSet)
+		out.DaemonSet = &daemonSet
+	}
+	if in.Job != nil {
+		job := v1beta1.JobSpec(*in.Job)
+		out.Job = &job
+	}
+	if in.CronJob != nil {
+		cronJob := v1beta1.CronJobSpec(*in.CronJob)
+		out.CronJob = &cronJob
+	}
+
+	if in.Autoscaling != nil {
+		autoscaling := v1beta1.AutoscalingSpec(*in.Autoscaling)
+		out.Autoscaling = &autoscaling
+	}
+	if in.DisruptionBudget != nil {
+		disruptionBudget := v1beta1.DisruptionBudgetSpec(*in.DisruptionBudget)
+		out.DisruptionBudget = &disruptionBudget
+	}
+	for _, cm := range in.ConfigMaps {
+		out.ConfigMaps = append(out.ConfigMaps, v1beta1.ConfigMapSpec{Name: cm.Name, Data: cm.Data, Files: convertConfigFilesToV1beta1(cm.Files)})
+	}
+	for _, secret := range in.Secrets {
+		out.Secrets = append(out.Secrets, v1beta1.SecretSpec{Name: secret.Name, Type: secret.Type, StringData: secret.StringData, Files: convertConfigFilesToV1beta1(secret.Files)})
+	}
+
+	if in.Service != nil {
+		out.Service = convertServiceToV1beta1(in.Service)
+	}
+	for i := range in.ExtraServices {
+		out.ExtraServices = append(out.ExtraServices, *convertServiceToV1beta1(&in.ExtraServices[i]))
+	}
+
+	if in.Ingress != nil {
+		out.Ingress = convertIngressToV1beta1(in.Ingress)
+	}
+	if in.Routing != nil {
+		out.Routing = &v1beta1.RoutingSpec{}
+		if in.Routing.Gateway != nil {
+			out.Routing.Gateway = convertGatewayRouteToV1beta1(in.Routing.Gateway)
+		}
+	}
+	if in.NetworkPolicy != nil {
+		out.NetworkPolicy = convertNetworkPolicyToV1beta1(in.NetworkPolicy)
+	}
+	if in.ServiceAccount != nil {
+		serviceAccount := v1beta1.ServiceAccountSpec(*in.ServiceAccount)
+		out.ServiceAccount = &serviceAccount
+	}
+
+	if p := in.Pvc; p != nil {
+		out.Pvc = &v1beta1.PvcSpec{
+			Name:              p.Name,
+			Storage:           p.Storage,
+			AccessModes:       p.AccessModes,
+			StorageClassName:  p.StorageClassName,
+			ForceDelete:       p.ForceDelete,
+			ReclaimPolicy:     v1beta1.PvcReclaimPolicy(p.ReclaimPolicy),
+			SnapshotClassName: p.SnapshotClassName,
+		}
+	}
+	return out
+}
+
+func convertSpecFromV1beta1(in *v1beta1.KubeAppSpec) KubeAppSpec {
+	out := KubeAppSpec{
+		EnableDeployment: in.EnableDeployment,
+		EnableService:    in.EnableService,
+		EnableIngress:    in.EnableIngress,
+		EnablePvc:        in.EnablePvc,
+		WorkloadType:     WorkloadType(in.WorkloadType),
+	}
+
+	if d := in.Deployment; d != nil {
+		out.Deployment = &DeploymentSpec{
+			Name:                          d.Name,
+			Image:                         d.Image,
+			Replicas:                      d.Replicas,
+			Resources:                     d.Resources,
+			Ports:                         d.Ports,
+			LivenessProbe:                 d.LivenessProbe,
+			ReadinessProbe:                d.ReadinessProbe,
+			Lifecycle:                     d.Lifecycle,
+			NodeSelector:                  d.NodeSelector,
+			Tolerations:                   d.Tolerations,
+			TerminationGracePeriodSeconds: d.TerminationGracePeriodSeconds,
+			ImagePullSecrets:              d.ImagePullSecrets,
+			DNSConfig:                     d.DNSConfig,
+			Affinity:                      d.Affinity,
+			Env:                           d.Env,
+		}
+		for _, v := range d.Volumes {
+			out.Deployment.Volumes = append(out.Deployment.Volumes, VolumeConfig(v))
+		}
+		for _, vm := range d.VolumeMounts {
+			out.Deployment.VolumeMounts = append(out.Deployment.VolumeMounts, VolumeMount(vm))
+		}
+		out.Deployment.InitContainers = convertContainersFromV1beta1(d.InitContainers)
+		out.Deployment.Sidecars = convertContainersFromV1beta1(d.Sidecars)
+		if d.Strategy != nil {
+			strategy := DeploymentStrategySpec(*d.Strategy)
+			out.Deployment.Strategy = &strategy
+		}
+		if d.SecurityProfile != nil {
+			profile := SecurityProfileSpec{
+				Type:                     SecurityProfileType(d.SecurityProfile.Type),
+				PodSecurityContext:       d.SecurityProfile.PodSecurityContext,
+				ContainerSecurityContext: d.SecurityProfile.ContainerSecurityContext,
+			}
+			out.Deployment.SecurityProfile = &profile
+		}
+	}
+
+	if sts := in.StatefulSet; sts != nil {
+		out.StatefulSet = &StatefulSetSpec{
+			ServiceName:         sts.ServiceName,
+			PodManagementPolicy: sts.PodManagementPolicy,
+		}
+		for _, t := range sts.VolumeClaimTemplates {
+			out.StatefulSet.VolumeClaimTemplates = append(out.StatefulSet.VolumeClaimTemplates, VolumeClaimTemplate(t))
+		}
+	}
+
+	if in.DaemonSet != nil {
+		daemonSet := DaemonSetSpec(*in.DaemonSet)
+		out.DaemonSet = &daemonSet
+	}
+	if in.Job != nil {
+		job := JobSpec(*in.Job)
+		out.Job = &job
+	}
+	if in.CronJob != nil {
+		cronJob := CronJobSpec(*in.CronJob)
+		out.CronJob = &cronJob
+	}
+
+	if in.Autoscaling != nil {
+		autoscaling := AutoscalingSpec(*in.Autoscaling)
+		out.Autoscaling = &autoscaling
+	}
+	if in.DisruptionBudget != nil {
+		disruptionBudget := DisruptionBudgetSpec(*in.DisruptionBudget)
+		out.DisruptionBudget = &disruptionBudget
+	}
+	for _, cm := range in.ConfigMaps {
+		out.ConfigMaps = append(out.ConfigMaps, ConfigMapSpec{Name: cm.Name, Data: cm.Data, Files: convertConfigFilesFromV1beta1(cm.Files)})
+	}
+	for _, secret := range in.Secrets {
+		out.Secrets = append(out.Secrets, SecretSpec{Name: secret.Name, Type: secret.Type, StringData: secret.StringData, Files: convertConfigFilesFromV1beta1(secret.Files)})
+	}
+
+	if in.Service != nil {
+		out.Service = convertServiceFromV1beta1(in.Service)
+	}
+	for i := range in.ExtraServices {
+		out.ExtraServices = append(out.ExtraServices, *convertServiceFromV1beta1(&in.ExtraServices[i]))
+	}
+
+	if in.Ingress != nil {
+		out.Ingress = convertIngressFromV1beta1(in.Ingress)
+	}
+	if in.Routing != nil {
+		out.Routing = &RoutingSpec{}
+		if in.Routing.Gateway != nil {
+			out.Routing.Gateway = convertGatewayRouteFromV1beta1(in.Routing.Gateway)
+		}
+	}
+	if in.NetworkPolicy != nil {
+		out.NetworkPolicy = convertNetworkPolicyFromV1beta1(in.NetworkPolicy)
+	}
+	if in.ServiceAccount != nil {
+		serviceAccount := ServiceAccountSpec(*in.ServiceAccount)
+		out.ServiceAccount = &serviceAccount
+	}
+
+	if p := in.Pvc; p != nil {
+		out.Pvc = &PvcSpec{
+			Name:              p.Name,
+			Storage:           p.Storage,
+			AccessModes:       p.AccessModes,
+			StorageClassName:  p.StorageClassName,
+			ForceDelete:       p.ForceDelete,
+			ReclaimPolicy:     PvcReclaimPolicy(p.ReclaimPolicy),
+			SnapshotClassName: p.SnapshotClassName,
+		}
+	}
+	return out
+}
+
+func convertContainersToV1beta1(in []ContainerSpec) []v1beta1.ContainerSpec {
+	if in == nil {
+		return nil
+	}
+	out := make([]v1beta1.ContainerSpec, 0, len(in))
+	for _, c := range in {
+		converted := v1beta1.ContainerSpec{
+			Name:           c.Name,
+			Image:          c.Image,
+			Command:        c.Command,
+			Args:           c.Args,
+			Env:            c.Env,
+			Resources:      c.Resources,
+			Ports:          c.Ports,
+			LivenessProbe:  c.LivenessProbe,
+			ReadinessProbe: c.ReadinessProbe,
+			StartupProbe:   c.StartupProbe,
+			RestartPolicy:  c.RestartPolicy,
+		}
+		for _, vm := range c.VolumeMounts {
+			converted.VolumeMounts = append(converted.VolumeMounts, v1beta1.VolumeMount(vm))
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+func convertContainersFromV1beta1(in []v1beta1.ContainerSpec) []ContainerSpec {
+	if in == nil {
+		return nil
+	}
+	out := make([]ContainerSpec, 0, len(in))
+	for _, c := range in {
+		converted := ContainerSpec{
+			Name:           c.Name,
+			Image:          c.Image,
+			Command:        c.Command,
+			Args:           c.Args,
+			Env:            c.Env,
+			Resources:      c.Resources,
+			Ports:          c.Ports,
+			LivenessProbe:  c.LivenessProbe,
+			ReadinessProbe: c.ReadinessProbe,
+			StartupProbe:   c.StartupProbe,
+			RestartPolicy:  c.RestartPolicy,
+		}
+		for _, vm := range c.VolumeMounts {
+			converted.VolumeMounts = append(converted.VolumeMounts, VolumeMount(vm))
+		}
+		out = append(out, converted)
+	}
+	return out
+}
+
+// convertIngressToV1beta1 writes the single-route fields as a one-rule, one-path list.
+func convertIngressToV1beta1(i *IngressSpec) *v1beta1.IngressSpec {
+	out := &v1beta1.IngressSpec{Name: i.Name, IngressClassName: i.IngressClassName, Annotations: i.Annotations}
+	for _, t := range i.TLS {
+		out.TLS = append(out.TLS, v1beta1.IngressTLS(t))
+	}
+	if len(i.Rules) > 0 {
+		for _, r := range i.Rules {
+			rule := v1beta1.IngressRule{Host: r.Host}
+			for _, p := range r.Paths {
+				rule.Paths = append(rule.Paths, v1beta1.IngressPath(p))
+			}
+			out.Rules = append(out.Rules, rule)
+		}
+		return out
+	}
+
+	path := v1beta1.IngressPath{Path: i.Path, PathType: i.PathType, ServiceName: i.ServiceName, ServicePort: i.ServicePort}
+	if i.Host != "" || path != (v1beta1.IngressPath{}) {
+		rule := v1beta1.IngressRule{Host: i.Host}
+		if path != (v1beta1.IngressPath{}) {
+			rule.Paths = []v1beta1.IngressPath{path}
+		}
+		out.Rules = []v1beta1.IngressRule{rule}
+	}
+	return out
+}
+
+// convertIngressFromV1beta1 always uses the rules list; the single-route fields only come
+// back from the ConversionDataAnnotation of an object written as v1alpha1.
+func convertIngressFromV1beta1(i *v1beta1.IngressSpec) *IngressSpec {
+	out := &IngressSpec{Name: i.Name, IngressClassName: i.IngressClassName, Annotations: i.Annotations}
+	for _, t := range i.TLS {
+		out.TLS = append(out.TLS, IngressTLS(t))
+	}
+	for _, r := range i.Rules {
+		rule := IngressRule{Host: r.Host}
+		for _, p := range r.Paths {
+			rule.Paths = append(rule.Paths, IngressPath(p))
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+	return out
+}
+
+// convertServiceToV1beta1 writes the single port/targetPort form as a one-element ports list.
+func convertServiceToV1beta1(s *ServiceSpec) *v1beta1.ServiceSpec {
+	out := &v1beta1.ServiceSpec{
+		Name:                          s.Name,
+		Type:                          s.Type,
+		Headless:                      s.Headless,
+		SessionAffinity:               s.SessionAffinity,
+		SessionAffinityTimeoutSeconds: s.SessionAffinityTimeoutSeconds,
+		ExternalTrafficPolicy:         s.ExternalTrafficPolicy,
+		LoadBalancerSourceRanges:      s.LoadBalancerSourceRanges,
+		Annotations:                   s.Annotations,
+	}
+	switch {
+	case len(s.Ports) > 0:
+		for _, p := range s.Ports {
+			out.Ports = append(out.Ports, v1beta1.ServicePort(p))
+		}
+	case s.Port != 0 || s.TargetPort != 0:
+		out.Ports = []v1beta1.ServicePort{{Port: s.Port, TargetPort: s.TargetPort}}
+	}
+	return out
+}
+
+// convertServiceFromV1beta1 always uses the ports list; the single port/targetPort form
+// only comes back from the ConversionDataAnnotation of an object written as v1alpha1.
+func convertServiceFromV1beta1(s *v1beta1.ServiceSpec) *ServiceSpec {
+	out := &ServiceSpec{
+		Name:                          s.Name,
+		Type:                          s.Type,
+		Headless:                      s.Headless,
+		SessionAffinity:               s.SessionAffinity,
+		SessionAffinityTimeoutSeconds: s.SessionAffinityTimeoutSeconds,
+		ExternalTrafficPolicy:         s.ExternalTrafficPolicy,
+		LoadBalancerSourceRanges:      s.LoadBalancerSourceRanges,
+		Annotations:                   s.Annotations,
+	}
+	for _, p := range s.Ports {
+		out.Ports = append(out.Ports, ServicePort(p))
+	}
+	return out
+}
+
+func convertGatewayRouteToV1beta1(g *GatewayRouteSpec) *v1beta1.GatewayRouteSpec {
+	out := &v1beta1.GatewayRouteSpec{Name: g.Name, Hostnames: g.Hostnames, Annotations: g.Annotations}
+	for _, ref := range g.ParentRefs {
+		out.ParentRefs = append(out.ParentRefs, v1beta1.GatewayParentRef(ref))
+	}
+	for _, r := range g.Rules {
+		rule := v1beta1.HTTPRouteRule{}
+		for _, m := range r.Matches {
+			match := v1beta1.HTTPRouteMatch{Path: m.Path, PathType: m.PathType}
+			for _, h := range m.Headers {
+				match.Headers = append(match.Headers, v1beta1.HTTPHeaderMatch(h))
+			}
+			rule.Matches = append(rule.Matches, match)
+		}
+		for _, b := range r.BackendRefs {
+			rule.BackendRefs = append(rule.BackendRefs, v1beta1.HTTPBackendRef(b))
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+	return out
+}
+
+func convertGatewayRouteFromV1beta1(g *v1beta1.GatewayRouteSpec) *GatewayRouteSpec {
+	out := &GatewayRouteSpec{Name: g.Name, Hostnames: g.Hostnames, Annotations: g.Annotations}
+	for _, ref := range g.ParentRefs {
+		out.ParentRefs = append(out.ParentRefs, GatewayParentRef(ref))
+	}
+	for _, r := range g.Rules {
+		rule := HTTPRouteRule{}
+		for _, m := range r.Matches {
+			match := HTTPRouteMatch{Path: m.Path, PathType: m.PathType}
+			for _, h := range m.Headers {
+				match.Headers = append(match.Headers, HTTPHeaderMatch(h))
+			}
+			rule.Matches = append(rule.Matches, match)
+		}
+		for _, b := range r.BackendRefs {
+			rule.BackendRefs = append(rule.BackendRefs, HTTPBackendRef(b))
+		}
+		out.Rules = append(out.Rules, rule)
+	}
+	return out
+}
+
+func convertNetworkPolicyToV1beta1(np *NetworkPolicySpec) *v1beta1.NetworkPolicySpec {
+	out := &v1beta1.NetworkPolicySpec{}
+	if in := np.IngressFrom; in != nil {
+		out.IngressFrom = &v1beta1.NetworkPolicyIngress{Namespaces: in.Namespaces}
+		for _, ref := range in.KubeApps {
+			out.IngressFrom.KubeApps = append(out.IngressFrom.KubeApps, v1beta1.KubeAppRef(ref))
+		}
+		if in.IngressController != nil {
+			ic := v1beta1.IngressControllerRef(*in.IngressController)
+			out.IngressFrom.IngressController = &ic
+		}
+	}
+	if eg := np.EgressTo; eg != nil {
+		out.EgressTo = &v1beta1.NetworkPolicyEgress{CIDRs: eg.CIDRs, DNS: eg.DNS}
+		for _, ref := range eg.KubeApps {
+			out.EgressTo.KubeApps = append(out.EgressTo.KubeApps, v1beta1.KubeAppRef(ref))
+		}
+	}
+	return out
+}
+
+func convertNetworkPolicyFromV1beta1(np *v1beta1.NetworkPolicySpec) *NetworkPolicySpec {
+	out := &NetworkPolicySpec{}
+	if in := np.IngressFrom; in != nil {
+		out.IngressFrom = &NetworkPolicyIngress{Namespaces: in.Namespaces}
+		for _, ref := range in.KubeApps {
+			out.IngressFrom.KubeApps = append(out.IngressFrom.KubeApps, KubeAppRef(ref))
+		}
+		if in.IngressController != nil {
+			ic := IngressControllerRef(*in.IngressController)
+			out.IngressFrom.IngressController = &ic
+		}
+	}
+	if eg := np.EgressTo; eg != nil {
+		out.EgressTo = &NetworkPolicyEgress{CIDRs: eg.CIDRs, DNS: eg.DNS}
+		for _, ref := range eg.KubeApps {
+			out.EgressTo.KubeApps = append(out.EgressTo.KubeApps, KubeAppRef(ref))
+		}
+	}
+	return out
+}
+
+func convertConfigFilesToV1beta1(in []ConfigFile) []v1beta1.ConfigFile {
+	var out []v1beta1.ConfigFile
+	for _, f := range in {
+		out = append(out, v1beta1.ConfigFile(f))
+	}
+	return out
+}
+
+func convertConfigFilesFromV1beta1(in []v1beta1.ConfigFile) []ConfigFile {
+	var out []ConfigFile
+	for _, f := range in {
+		out = append(out, ConfigFile(f))
+	}
+	return out
+}
+
+func convertStatusToV1beta1(in *KubeAppStatus) v1beta1.KubeAppStatus {
+	out := v1beta1.KubeAppStatus{
+		Nodes:              in.Nodes,
+		ObservedGeneration: in.ObservedGeneration,
+		CurrentRevision:    in.CurrentRevision,
+		Conditions:         in.Conditions,
+	}
+	if in.Deployment != nil {
+		d := v1beta1.DeploymentStatusSummary(*in.Deployment)
+		out.Deployment = &d
+	}
+	if in.Job != nil {
+		j := v1beta1.JobStatusSummary(*in.Job)
+		out.Job = &j
+	}
+	if in.Autoscaling != nil {
+		a := v1beta1.AutoscalingStatusSummary(*in.Autoscaling)
+		out.Autoscaling = &a
+	}
+	if in.Service != nil {
+		s := v1beta1.ServiceStatusSummary(*in.Service)
+		out.Service = &s
+	}
+	if in.Ingress != nil {
+		i := v1beta1.IngressStatusSummary(*in.Ingress)
+		out.Ingress = &i
+	}
+	if in.HTTPRoute != nil {
+		h := v1beta1.HTTPRouteStatusSummary(*in.HTTPRoute)
+		out.HTTPRoute = &h
+	}
+	if in.Pvc != nil {
+		p := v1beta1.PvcStatusSummary(*in.Pvc)
+		out.Pvc = &p
+	}
+	return out
+}
+
+func convertStatusFromV1beta1(in *v1beta1.KubeAppStatus) KubeAppStatus {
+	out := KubeAppStatus{
+		Nodes:              in.Nodes,
+		ObservedGeneration: in.ObservedGeneration,
+		CurrentRevision:    in.CurrentRevision,
+		Conditions:         in.Conditions,
+	}
+	if in.Deployment != nil {
+		d := DeploymentStatusSummary(*in.Deployment)
+		out.Deployment = &d
+	}
+	if in.Job != nil {
+		j := JobStatusSummary(*in.Job)
+		out.Job = &j
+	}
+	if in.Autoscaling != nil {
+		a := AutoscalingStatusSummary(*in.Autoscaling)
+		out.Autoscaling = &a
+	}
+	if in.Service != nil {
+		s := ServiceStatusSummary(*in.Service)
+		out.Service = &s
+	}
+	if in.Ingress != nil {
+		i := IngressStatusSummary(*in.Ingress)
+		out.Ingress = &i
+	}
+	if in.HTTPRoute != nil {
+		h := HTTPRouteStatusSummary(*in.HTTPRoute)
+		out.HTTPRoute = &h
+	}
+	if in.Pvc != nil {
+		p := PvcStatusSummary(*in.Pvc)
+		out.Pvc = &p
+	}
+	return out
+}
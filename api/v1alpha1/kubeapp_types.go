@@ -17,9 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -37,6 +42,64 @@ type KubeAppSpec struct {
 	Deployment       *DeploymentSpec `json:"deployment,omitempty"` // Citation deployments struct
 	Service          *ServiceSpec    `json:"service,omitempty"`    // Citation service  struct
 	Ingress          *IngressSpec    `json:"ingress,omitempty"`    // Citation ingress struct
+
+	// WorkloadType selects the controller generated from spec.deployment: Deployment
+	// (default), StatefulSet, DaemonSet, Job or CronJob.
+	// +optional
+	WorkloadType WorkloadType `json:"workloadType,omitempty"`
+	// StatefulSet holds the settings used when workloadType is StatefulSet.
+	// +optional
+	StatefulSet *StatefulSetSpec `json:"statefulSet,omitempty"`
+	// DaemonSet holds the rollout settings used when workloadType is DaemonSet.
+	// +optional
+	DaemonSet *DaemonSetSpec `json:"daemonSet,omitempty"`
+	// Job holds the Job settings used when workloadType is Job or CronJob.
+	// +optional
+	Job *JobSpec `json:"job,omitempty"`
+	// CronJob holds the schedule used when workloadType is CronJob.
+	// +optional
+	CronJob *CronJobSpec `json:"cronJob,omitempty"`
+
+	// Autoscaling generates a HorizontalPodAutoscaler for the Deployment or StatefulSet.
+	// While it is set the operator no longer manages the replica count of the workload.
+	// +optional
+	Autoscaling *AutoscalingSpec `json:"autoscaling,omitempty"`
+	// DisruptionBudget generates a PodDisruptionBudget for the Deployment or StatefulSet.
+	// It is skipped, with a warning in status, while the workload runs a single replica.
+	// +optional
+	DisruptionBudget *DisruptionBudgetSpec `json:"disruptionBudget,omitempty"`
+
+	// ConfigMaps are created as ConfigMaps owned by the KubeApp. Their content is hashed
+	// into the pod template, so a change rolls out the workload.
+	// +optional
+	ConfigMaps []ConfigMapSpec `json:"configMaps,omitempty"`
+	// Secrets are created as Secrets owned by the KubeApp and hashed into the pod
+	// template like configMaps.
+	// +optional
+	Secrets []SecretSpec `json:"secrets,omitempty"`
+	// ExtraServices are created next to spec.service while enableService is true, for
+	// example an internal Service that only exposes the metrics port. They select the
+	// same pods as spec.service.
+	// +optional
+	ExtraServices []ServiceSpec `json:"extraServices,omitempty"`
+	// Routing generates Gateway API routes next to, or instead of, the Ingress.
+	// +optional
+	Routing *RoutingSpec `json:"routing,omitempty"`
+	// NetworkPolicy generates a NetworkPolicy for the pods of the workload.
+	// +optional
+	NetworkPolicy *NetworkPolicySpec `json:"networkPolicy,omitempty"`
+	// ServiceAccount creates a ServiceAccount owned by the KubeApp and runs the pods
+	// as it instead of the namespace default ServiceAccount.
+	// +optional
+	ServiceAccount *ServiceAccountSpec `json:"serviceAccount,omitempty"`
+}
+
+// EffectiveWorkloadType returns the workload type, defaulting to Deployment.
+func (s *KubeAppSpec) EffectiveWorkloadType() WorkloadType {
+	if s.WorkloadType == "" {
+		return WorkloadDeployment
+	}
+	return s.WorkloadType
 }
 
 type DeploymentSpec struct {
@@ -51,6 +114,9 @@ type DeploymentSpec struct {
 	Lifecycle       *corev1.Lifecycle    `json:"lifecycle,omitempty"`
 
 	NodeSelector    map[string]string    `json:"nodeSelector,omitempty"`
+	// Tolerations let the pods run on tainted nodes, e.g. control-plane nodes for a DaemonSet.
+	// +optional
+	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 	Volumes         []VolumeConfig       `json:"volumes,omitempty"`
 	VolumeMounts    []VolumeMount  `json:"volumeMounts,omitempty"`
 
@@ -60,21 +126,347 @@ type DeploymentSpec struct {
 
 	Affinity    *corev1.Affinity  `json:"affinity,omitempty"`
 	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// InitContainers run in order and must complete before the main container starts.
+	// +optional
+	InitContainers []ContainerSpec `json:"initContainers,omitempty"`
+	// Sidecars run next to the main container. A sidecar with restartPolicy Always is
+	// started as a native sidecar, before the init containers, and kept running for the
+	// lifetime of the pod.
+	// +optional
+	Sidecars []ContainerSpec `json:"sidecars,omitempty"`
+
+	// Strategy controls how the generated Deployment replaces its pods.
+	// It is ignored by the other workload types.
+	// +optional
+	Strategy *DeploymentStrategySpec `json:"strategy,omitempty"`
+
+	// SecurityProfile sets the securityContext of the pod and of every container,
+	// including init containers and sidecars. Without it no securityContext is set.
+	// +optional
+	SecurityProfile *SecurityProfileSpec `json:"securityProfile,omitempty"`
+}
+
+// SecurityProfileType names a Pod Security Standards level, or custom.
+// +kubebuilder:validation:Enum=restricted;baseline;custom
+type SecurityProfileType string
+
+const (
+	// SecurityProfileRestricted runs as non-root with the RuntimeDefault seccomp profile,
+	// no privilege escalation and all capabilities dropped.
+	SecurityProfileRestricted SecurityProfileType = "restricted"
+	// SecurityProfileBaseline uses the RuntimeDefault seccomp profile, forbids privilege
+	// escalation and drops NET_RAW.
+	SecurityProfileBaseline SecurityProfileType = "baseline"
+	// SecurityProfileCustom applies podSecurityContext and containerSecurityContext as given.
+	SecurityProfileCustom SecurityProfileType = "custom"
+)
+
+// SecurityProfileSpec selects the securityContext of the generated pods.
+type SecurityProfileSpec struct {
+	Type SecurityProfileType `json:"type"`
+	// PodSecurityContext is only used with the custom profile.
+	// +optional
+	PodSecurityContext *corev1.PodSecurityContext `json:"podSecurityContext,omitempty"`
+	// ContainerSecurityContext is only used with the custom profile and is applied
+	// to every container.
+	// +optional
+	ContainerSecurityContext *corev1.SecurityContext `json:"containerSecurityContext,omitempty"`
+}
+
+// DeploymentStrategySpec describes the rollout of the generated Deployment.
+type DeploymentStrategySpec struct {
+	// Type is RollingUpdate (default) or Recreate. Use Recreate when the pods mount a
+	// ReadWriteOnce volume that the old and the new pod cannot share.
+	// +kubebuilder:validation:Enum=RollingUpdate;Recreate
+	// +optional
+	Type appsv1.DeploymentStrategyType `json:"type,omitempty"`
+	// MaxSurge is the number or percentage of pods created above the desired replicas
+	// during a RollingUpdate. Defaults to 25%.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaxUnavailable is the number or percentage of pods that may be unavailable
+	// during a RollingUpdate. Defaults to 25%.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+	// MinReadySeconds is how long a new pod must be ready before it counts as available.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+	// ProgressDeadlineSeconds is how long a rollout may make no progress before the
+	// Deployment reports ProgressDeadlineExceeded. Kubernetes defaults it to 600.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+	// RevisionHistoryLimit is the number of old ReplicaSets kept for rollback.
+	// Kubernetes defaults it to 10.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// ContainerSpec describes an init container or a sidecar of the generated pod.
+type ContainerSpec struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+	// +optional
+	Command []string `json:"command,omitempty"`
+	// +optional
+	Args []string `json:"args,omitempty"`
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// +optional
+	Ports []corev1.ContainerPort `json:"ports,omitempty"`
+
+	// Probes are only allowed on sidecars, including native sidecars.
+	// +optional
+	LivenessProbe *corev1.Probe `json:"livenessProbe,omitempty"`
+	// +optional
+	ReadinessProbe *corev1.Probe `json:"readinessProbe,omitempty"`
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// VolumeMounts mount volumes declared in deployment.volumes.
+	// +optional
+	VolumeMounts []VolumeMount `json:"volumeMounts,omitempty"`
+
+	// RestartPolicy may only be Always, which makes the container a native sidecar.
+	// +kubebuilder:validation:Enum=Always
+	// +optional
+	RestartPolicy *corev1.ContainerRestartPolicy `json:"restartPolicy,omitempty"`
+}
+
+// WorkloadType selects the controller generated from spec.deployment.
+// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet;Job;CronJob
+type WorkloadType string
+
+const (
+	// WorkloadDeployment generates a Deployment. It is the default.
+	WorkloadDeployment WorkloadType = "Deployment"
+	// WorkloadStatefulSet generates a StatefulSet and its headless governing Service.
+	WorkloadStatefulSet WorkloadType = "StatefulSet"
+	// WorkloadDaemonSet runs one pod per matching node; deployment.replicas is ignored.
+	WorkloadDaemonSet WorkloadType = "DaemonSet"
+	// WorkloadJob runs the pod to completion once. A spec change replaces the Job.
+	WorkloadJob WorkloadType = "Job"
+	// WorkloadCronJob runs the pod as a Job on a schedule.
+	WorkloadCronJob WorkloadType = "CronJob"
+)
+
+// StatefulSetSpec holds the settings used when workloadType is StatefulSet.
+type StatefulSetSpec struct {
+	// ServiceName is the headless governing Service; defaults to "<deployment.name>-headless".
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// PodManagementPolicy is OrderedReady (default) or Parallel.
+	// +kubebuilder:validation:Enum=OrderedReady;Parallel
+	// +optional
+	PodManagementPolicy appsv1.PodManagementPolicyType `json:"podManagementPolicy,omitempty"`
+	// VolumeClaimTemplates give every replica its own PVC. Mount them through
+	// deployment.volumeMounts by template name.
+	// +optional
+	VolumeClaimTemplates []VolumeClaimTemplate `json:"volumeClaimTemplates,omitempty"`
+}
+
+// DaemonSetSpec holds the rollout settings used when workloadType is DaemonSet.
+// The nodes it runs on are selected with deployment.nodeSelector, deployment.tolerations
+// and deployment.affinity.
+type DaemonSetSpec struct {
+	// UpdateStrategy is RollingUpdate (default) or OnDelete.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +optional
+	UpdateStrategy appsv1.DaemonSetUpdateStrategyType `json:"updateStrategy,omitempty"`
+	// MaxUnavailable is the number or percentage of nodes whose pod may be unavailable
+	// during a RollingUpdate. Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// AutoscalingSpec describes the generated autoscaling/v2 HorizontalPodAutoscaler.
+// Without any target or metric the HPA scales on 80% average CPU utilization.
+type AutoscalingSpec struct {
+	// MinReplicas defaults to deployment.replicas.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas int32 `json:"maxReplicas"`
+	// TargetCPUUtilizationPercentage is the average CPU utilization, relative to the
+	// container requests, that the HPA keeps the pods at.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetCPUUtilizationPercentage *int32 `json:"targetCPUUtilizationPercentage,omitempty"`
+	// TargetMemoryUtilizationPercentage is the average memory utilization, relative to
+	// the container requests, that the HPA keeps the pods at.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TargetMemoryUtilizationPercentage *int32 `json:"targetMemoryUtilizationPercentage,omitempty"`
+	// Metrics are added after the CPU and memory targets, e.g. Pods or External metrics
+	// served by a custom metrics adapter.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+	// Behavior configures the scale-up and scale-down policies.
+	// +optional
+	Behavior *autoscalingv2.HorizontalPodAutoscalerBehavior `json:"behavior,omitempty"`
+}
+
+// DisruptionBudgetSpec describes the generated policy/v1 PodDisruptionBudget.
+// Exactly one of minAvailable and maxUnavailable must be set.
+type DisruptionBudgetSpec struct {
+	// MinAvailable is the number or percentage of pods that must stay available during
+	// voluntary disruptions such as node drains.
+	// +optional
+	MinAvailable *intstr.IntOrString `json:"minAvailable,omitempty"`
+	// MaxUnavailable is the number or percentage of pods that may be unavailable during
+	// voluntary disruptions.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// ConfigMapSpec describes a ConfigMap created by the operator. Volumes, env and envFrom
+// refer to it by name like any other ConfigMap.
+type ConfigMapSpec struct {
+	// Name of the generated ConfigMap.
+	Name string `json:"name"`
+	// Data holds literal key/value pairs.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+	// Files holds whole files, such as the config files of an application template.
+	// +optional
+	Files []ConfigFile `json:"files,omitempty"`
+}
+
+// SecretSpec describes a Secret created by the operator.
+type SecretSpec struct {
+	// Name of the generated Secret.
+	Name string `json:"name"`
+	// Type of the Secret, Opaque by default.
+	// +optional
+	Type corev1.SecretType `json:"type,omitempty"`
+	// StringData holds literal values in plain text; the API server stores them encoded.
+	// +optional
+	StringData map[string]string `json:"stringData,omitempty"`
+	// Files holds whole files, such as certificates or credential files.
+	// +optional
+	Files []ConfigFile `json:"files,omitempty"`
+}
+
+// ConfigFile is a file stored in a ConfigMap or Secret.
+type ConfigFile struct {
+	// Name is the key, and the file name when the object is mounted as a volume.
+	Name string `json:"name"`
+	// Content of the file.
+	Content string `json:"content"`
+}
+
+// JobSpec holds the Job settings used when workloadType is Job or CronJob.
+type JobSpec struct {
+	// BackoffLimit is the number of retries before the Job is marked failed.
+	// +optional
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+	// TTLSecondsAfterFinished removes finished Jobs (and their pods) after this many seconds.
+	// +optional
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+	// ActiveDeadlineSeconds bounds the run time of the Job.
+	// +optional
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// +optional
+	Completions *int32 `json:"completions,omitempty"`
+	// +optional
+	Parallelism *int32 `json:"parallelism,omitempty"`
+	// RestartPolicy of the pod, OnFailure (default) or Never.
+	// +kubebuilder:validation:Enum=OnFailure;Never
+	// +optional
+	RestartPolicy corev1.RestartPolicy `json:"restartPolicy,omitempty"`
+}
+
+// CronJobSpec holds the schedule used when workloadType is CronJob. The Jobs it
+// creates use spec.job.
+type CronJobSpec struct {
+	// Schedule in cron format, e.g. "*/5 * * * *".
+	Schedule string `json:"schedule"`
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+	// ConcurrencyPolicy is Allow (default), Forbid or Replace.
+	// +kubebuilder:validation:Enum=Allow;Forbid;Replace
+	// +optional
+	ConcurrencyPolicy batchv1.ConcurrencyPolicy `json:"concurrencyPolicy,omitempty"`
+	// +optional
+	Suspend *bool `json:"suspend,omitempty"`
+	// +optional
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+	// +optional
+	SuccessfulJobsHistoryLimit *int32 `json:"successfulJobsHistoryLimit,omitempty"`
+	// +optional
+	FailedJobsHistoryLimit *int32 `json:"failedJobsHistoryLimit,omitempty"`
+}
+
+// VolumeClaimTemplate describes the per-replica PVC of a StatefulSet.
+type VolumeClaimTemplate struct {
+	Name    string `json:"name"`
+	Storage string `json:"storage"`
+	// +optional
+	AccessModes []corev1.PersistentVolumeAccessMode `json:"accessModes,omitempty"`
+	// +optional
+	StorageClassName *string `json:"storageClassName,omitempty"`
 }
 
 // defines service spec field object
 
 type ServiceSpec struct {
-	Name       string `json:"name"`
+	Name string `json:"name"`
+	// Port and TargetPort describe a single unnamed port. They are ignored when ports is set.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// +optional
+	TargetPort int32 `json:"targetPort,omitempty"`
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+	// Ports lists several named ports, for example http, grpc and metrics.
+	// +optional
+	Ports []ServicePort `json:"ports,omitempty"`
+	// Headless creates the Service with clusterIP None, so DNS returns the pod IPs.
+	// +optional
+	Headless bool `json:"headless,omitempty"`
+	// SessionAffinity pins a client to one pod when set to ClientIP.
+	// +kubebuilder:validation:Enum=None;ClientIP
+	// +optional
+	SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+	// SessionAffinityTimeoutSeconds is how long ClientIP affinity is kept, 10800 by default.
+	// +optional
+	SessionAffinityTimeoutSeconds *int32 `json:"sessionAffinityTimeoutSeconds,omitempty"`
+	// ExternalTrafficPolicy of a NodePort or LoadBalancer Service. Local keeps the client
+	// source IP and only routes to pods on the receiving node.
+	// +kubebuilder:validation:Enum=Cluster;Local
+	// +optional
+	ExternalTrafficPolicy corev1.ServiceExternalTrafficPolicy `json:"externalTrafficPolicy,omitempty"`
+	// LoadBalancerSourceRanges restricts the client CIDRs a LoadBalancer Service accepts.
+	// +optional
+	LoadBalancerSourceRanges []string `json:"loadBalancerSourceRanges,omitempty"`
+	// Annotations are added to the Service, typically cloud LoadBalancer settings.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// ServicePort is one port exposed by the Service.
+type ServicePort struct {
+	// Name is required when the Service has more than one port.
+	// +optional
+	Name       string `json:"name,omitempty"`
 	Port       int32  `json:"port"`
 	TargetPort int32  `json:"targetPort"`
-	Type       corev1.ServiceType `json:"type"`
+	// +optional
+	Protocol corev1.Protocol `json:"protocol,omitempty"`
 }
 
 // defines ingress spec field object
 
 type IngressSpec struct {
 	Name        string `json:"name,omitempty"`
+	// Host, ServiceName, ServicePort, Path and PathType describe a single route.
+	// They are ignored when rules is set.
 	Host        string `json:"host,omitempty"`
 	ServiceName string `json:"service_name,omitempty"`
 	ServicePort int32  `json:"service_port,omitempty"`
@@ -82,6 +474,221 @@ type IngressSpec struct {
 	PathType    networkingv1.PathType `json:"path_type,omitempty"`
 	IngressClassName string                  `json:"ingressClassName,omitempty"`
 
+	// Rules lists several hosts, each with its own paths and backends.
+	// +optional
+	Rules []IngressRule `json:"rules,omitempty"`
+	// TLS terminates HTTPS for the listed hosts.
+	// +optional
+	TLS []IngressTLS `json:"tls,omitempty"`
+	// Annotations are added to the Ingress, typically ingress controller settings.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IngressRule routes the paths of one host.
+type IngressRule struct {
+	// +optional
+	Host string `json:"host,omitempty"`
+	// +optional
+	Paths []IngressPath `json:"paths,omitempty"`
+}
+
+// IngressPath sends one path to a Service port.
+type IngressPath struct {
+	// +optional
+	Path string `json:"path,omitempty"`
+	// +optional
+	PathType networkingv1.PathType `json:"pathType,omitempty"`
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// +optional
+	ServicePort int32 `json:"servicePort,omitempty"`
+}
+
+// IngressTLS terminates TLS for a set of hosts with the certificate in secretName.
+type IngressTLS struct {
+	Hosts []string `json:"hosts"`
+	// SecretName of the kubernetes.io/tls Secret. Left empty, the ingress controller
+	// uses its default certificate.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+// RoutingSpec groups the Gateway API routes generated next to, or instead of, the Ingress.
+type RoutingSpec struct {
+	// Gateway generates a gateway.networking.k8s.io/v1 HTTPRoute. The Gateway API CRDs
+	// must be installed in the cluster.
+	// +optional
+	Gateway *GatewayRouteSpec `json:"gateway,omitempty"`
+}
+
+// GatewayRouteSpec describes the generated HTTPRoute.
+type GatewayRouteSpec struct {
+	// Name of the HTTPRoute, defaults to the KubeApp name.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// ParentRefs are the Gateways, or listeners of them, the route attaches to.
+	// +kubebuilder:validation:MinItems=1
+	ParentRefs []GatewayParentRef `json:"parentRefs"`
+	// Hostnames matched against the Host header; a leading wildcard such as
+	// *.example.com is allowed.
+	// +optional
+	Hostnames []string `json:"hostnames,omitempty"`
+	// Rules route matching requests to weighted backends. Without rules every request
+	// goes to spec.service.
+	// +optional
+	Rules []HTTPRouteRule `json:"rules,omitempty"`
+	// Annotations are added to the HTTPRoute.
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// GatewayParentRef references a Gateway the HTTPRoute attaches to.
+type GatewayParentRef struct {
+	Name string `json:"name"`
+	// Namespace of the Gateway, defaults to the namespace of the KubeApp.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// SectionName attaches to a single listener of the Gateway.
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+	// Port attaches to the listeners of the Gateway on this port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port *int32 `json:"port,omitempty"`
+}
+
+// HTTPRouteRule sends requests that match any of matches to backendRefs.
+type HTTPRouteRule struct {
+	// Matches are ORed; without matches the rule matches every request.
+	// +optional
+	Matches []HTTPRouteMatch `json:"matches,omitempty"`
+	// BackendRefs share the traffic by weight. Without backendRefs the rule goes to
+	// the first port of spec.service.
+	// +optional
+	BackendRefs []HTTPBackendRef `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteMatch matches a request by path and headers; all set conditions must match.
+type HTTPRouteMatch struct {
+	// Path defaults to /.
+	// +optional
+	Path string `json:"path,omitempty"`
+	// PathType defaults to PathPrefix.
+	// +kubebuilder:validation:Enum=Exact;PathPrefix;RegularExpression
+	// +optional
+	PathType string `json:"pathType,omitempty"`
+	// +optional
+	Headers []HTTPHeaderMatch `json:"headers,omitempty"`
+}
+
+// HTTPHeaderMatch matches the value of one request header.
+type HTTPHeaderMatch struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Type defaults to Exact.
+	// +kubebuilder:validation:Enum=Exact;RegularExpression
+	// +optional
+	Type string `json:"type,omitempty"`
+}
+
+// HTTPBackendRef is a Service port receiving a share of the traffic.
+type HTTPBackendRef struct {
+	// Name of the Service, defaults to spec.service.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Port of the Service, defaults to the first port of spec.service.
+	// +optional
+	Port int32 `json:"port,omitempty"`
+	// Weight is the relative share of the traffic, defaults to 1. 0 stops sending
+	// traffic to the backend.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1000000
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
+}
+
+// NetworkPolicySpec describes the generated NetworkPolicy. It selects the pods of the
+// KubeApp by their app label and only allows the declared traffic.
+type NetworkPolicySpec struct {
+	// IngressFrom lists who may connect to the pods; set but empty, all inbound traffic
+	// is denied. Left unset, inbound traffic is only restricted when egressTo is unset
+	// too, so that an empty networkPolicy still isolates the pods.
+	// +optional
+	IngressFrom *NetworkPolicyIngress `json:"ingressFrom,omitempty"`
+	// EgressTo lists where the pods may connect to. Left unset, outbound traffic is not
+	// restricted by this policy.
+	// +optional
+	EgressTo *NetworkPolicyEgress `json:"egressTo,omitempty"`
+}
+
+// NetworkPolicyIngress lists the sources allowed to reach the pods.
+type NetworkPolicyIngress struct {
+	// Namespaces allows every pod in these namespaces.
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// KubeApps allows the pods of other KubeApps.
+	// +optional
+	KubeApps []KubeAppRef `json:"kubeApps,omitempty"`
+	// IngressController allows the pods of the ingress or gateway controller.
+	// +optional
+	IngressController *IngressControllerRef `json:"ingressController,omitempty"`
+}
+
+// NetworkPolicyEgress lists the destinations the pods may reach.
+type NetworkPolicyEgress struct {
+	// KubeApps allows connections to the pods of other KubeApps.
+	// +optional
+	KubeApps []KubeAppRef `json:"kubeApps,omitempty"`
+	// CIDRs allows connections to IP blocks, for example a managed database.
+	// +optional
+	CIDRs []networkingv1.IPBlock `json:"cidrs,omitempty"`
+	// DNS allows DNS lookups against kube-dns in kube-system.
+	// +optional
+	DNS bool `json:"dns,omitempty"`
+}
+
+// KubeAppRef references another KubeApp; its pods are selected by the app label of
+// its Deployment.
+type KubeAppRef struct {
+	Name string `json:"name"`
+	// Namespace of the KubeApp, defaults to the namespace of this KubeApp.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// IngressControllerRef selects the ingress controller pods.
+type IngressControllerRef struct {
+	// Namespace the controller runs in, defaults to ingress-nginx.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// PodLabels narrows the selection to the controller pods; left empty, every pod
+	// in the namespace is allowed.
+	// +optional
+	PodLabels map[string]string `json:"podLabels,omitempty"`
+}
+
+// ServiceAccountSpec describes the ServiceAccount the pods run as and, optionally,
+// the namespaced permissions granted to it.
+type ServiceAccountSpec struct {
+	// Name of the ServiceAccount, defaults to the KubeApp name. The Role and
+	// RoleBinding use the same name.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Annotations are added to the ServiceAccount, for example the cloud IAM role
+	// binding (eks.amazonaws.com/role-arn, iam.gke.io/gcp-service-account).
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// AutomountServiceAccountToken controls whether the API token is mounted into the
+	// pods. Set it to false for workloads that never call the Kubernetes API.
+	// +optional
+	AutomountServiceAccountToken *bool `json:"automountServiceAccountToken,omitempty"`
+	// Rules generates a Role with these rules bound to the ServiceAccount. Left empty,
+	// no Role or RoleBinding is created. The rules must be a subset of the operator's
+	// own permissions; the API server rejects a Role that grants more.
+	// +optional
+	Rules []rbacv1.PolicyRule `json:"rules,omitempty"`
 }
 
 // volumeMount define
@@ -113,25 +720,230 @@ type VolumeConfig struct {
 }
 
 
+// PvcReclaimPolicy decides what happens to the PVC when the KubeApp is deleted
+// or enablePvc is switched off.
+// +kubebuilder:validation:Enum=Retain;Delete;Snapshot
+type PvcReclaimPolicy string
+
+const (
+	// PvcReclaimRetain keeps the PVC and its data.
+	PvcReclaimRetain PvcReclaimPolicy = "Retain"
+	// PvcReclaimDelete deletes the PVC.
+	PvcReclaimDelete PvcReclaimPolicy = "Delete"
+	// PvcReclaimSnapshot takes a VolumeSnapshot and deletes the PVC once it is ready to use.
+	PvcReclaimSnapshot PvcReclaimPolicy = "Snapshot"
+)
+
 type PvcSpec struct {
 	Name             string                              `json:"name"`
 	Storage          string                              `json:"storage"` // e.g. "1Gi"
 	AccessModes      []corev1.PersistentVolumeAccessMode `json:"accessModes"`
 	StorageClassName *string                             `json:"storageClassName,omitempty"`
-	ForceDelete bool `json:"forceDelete,omitempty"`  // 是否强制删除 PVC
+	ForceDelete bool `json:"forceDelete,omitempty"`  // 是否强制删除 PVC，已由 reclaimPolicy 取代，仅在其为空时生效
+
+	// ReclaimPolicy is one of Retain, Delete or Snapshot. When empty, forceDelete=true
+	// means Delete and anything else means Retain.
+	// +optional
+	ReclaimPolicy PvcReclaimPolicy `json:"reclaimPolicy,omitempty"`
+	// SnapshotClassName is the VolumeSnapshotClass used by the Snapshot policy.
+	// +optional
+	SnapshotClassName *string `json:"snapshotClassName,omitempty"`
 }
 
+// EffectiveReclaimPolicy returns the reclaim policy, honouring the legacy forceDelete flag.
+func (p *PvcSpec) EffectiveReclaimPolicy() PvcReclaimPolicy {
+	if p == nil {
+		return PvcReclaimRetain
+	}
+	if p.ReclaimPolicy != "" {
+		return p.ReclaimPolicy
+	}
+	if p.ForceDelete {
+		return PvcReclaimDelete
+	}
+	return PvcReclaimRetain
+}
 
 
+
+// Condition types reported in KubeAppStatus.Conditions.
+const (
+	// ConditionReady is True when every enabled child resource is available.
+	ConditionReady = "Ready"
+	// ConditionProgressing is True while the Deployment is still rolling out.
+	ConditionProgressing = "Progressing"
+	// ConditionDegraded is True when a child resource reports a failure.
+	ConditionDegraded = "Degraded"
+	// ConditionReconcileError is True when the last reconcile returned an error.
+	ConditionReconcileError = "ReconcileError"
+	// ConditionTerminating reports the current teardown step while the KubeApp is being deleted.
+	ConditionTerminating = "Terminating"
+	// ConditionFieldConflict is True when a server-side apply of a child resource was
+	// rejected because another field manager owns some of the fields.
+	ConditionFieldConflict = "FieldConflict"
+	// ConditionDisruptionBudget reports whether the PodDisruptionBudget requested by
+	// spec.disruptionBudget exists. It is False while the workload runs a single replica.
+	ConditionDisruptionBudget = "DisruptionBudget"
+	// ConditionPodSecurity reports whether the generated pods satisfy the Pod Security
+	// Admission level enforced on the namespace. It is absent when the namespace does
+	// not enforce a level.
+	ConditionPodSecurity = "PodSecurity"
+	// ConditionPaused is True while reconciliation or the Deployment rollout is paused
+	// through the ReconcileAnnotation or the RolloutAnnotation.
+	ConditionPaused = "Paused"
+)
+
+// Annotations and labels recognised by the operator.
+const (
+	// SelfHealAnnotation set to "disabled" stops the operator from reverting manual
+	// edits to child resources; children are re-applied only when the spec changes.
+	SelfHealAnnotation = "kubeapp.io/self-heal"
+	// NameLabel records the owning KubeApp on children that carry no ownerReference.
+	NameLabel = "kubeapp.io/name"
+	// Finalizer holds the KubeApp until its children have been torn down.
+	Finalizer = "apps.kube.com/finalizer"
+	// ConversionDataAnnotation carries the spec of the other API version when a conversion
+	// is not exact, so that an unedited object converts back unchanged.
+	// It is managed by the conversion webhook and must not be copied onto child resources.
+	ConversionDataAnnotation = "kubeapp.io/conversion-data"
+	// SpecHashAnnotation records the hash of the Job spec the operator applied. The Job
+	// template is immutable, so a Job with a different hash is deleted and created again.
+	SpecHashAnnotation = "kubeapp.io/spec-hash"
+	// ConfigHashAnnotation on the pod template records the hash of spec.configMaps and
+	// spec.secrets, so that changing them starts a rollout.
+	ConfigHashAnnotation = "kubeapp.io/config-hash"
+	// ReconcileAnnotation set to "paused" freezes the KubeApp: the operator stops creating,
+	// updating and deleting its children but keeps reporting status. Deleting the
+	// KubeApp still tears the children down.
+	ReconcileAnnotation = "kubeapp.io/reconcile"
+	// RolloutAnnotation set to "paused" pauses the rollout of the generated Deployment
+	// through its spec.paused; other children are still reconciled.
+	RolloutAnnotation = "kubeapp.io/rollout"
+	// PausedValue is the value of ReconcileAnnotation and RolloutAnnotation that pauses.
+	PausedValue = "paused"
+)
+
+// DeploymentStatusSummary is the observed state of the generated Deployment.
+type DeploymentStatusSummary struct {
+	Name              string `json:"name"`
+	// Kind is the workload kind, Deployment, StatefulSet or DaemonSet.
+	// +optional
+	Kind              string `json:"kind,omitempty"`
+	Replicas          int32  `json:"replicas"`
+	ReadyReplicas     int32  `json:"readyReplicas"`
+	UpdatedReplicas   int32  `json:"updatedReplicas"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+}
+
+// JobStatusSummary is the observed state of the generated Job or CronJob.
+type JobStatusSummary struct {
+	Name string `json:"name"`
+	// Kind is Job or CronJob.
+	Kind string `json:"kind"`
+	// Active is the number of running pods (Job) or running Jobs (CronJob).
+	// +optional
+	Active int32 `json:"active,omitempty"`
+	// LastRunTime is when the last run started.
+	// +optional
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// LastSuccessfulTime is when the last successful run finished.
+	// +optional
+	LastSuccessfulTime *metav1.Time `json:"lastSuccessfulTime,omitempty"`
+	// LastRunResult is Running, Succeeded or Failed.
+	// +optional
+	LastRunResult string `json:"lastRunResult,omitempty"`
+	// SpecHash is the kubeapp.io/spec-hash of the Job that last finished (Job only). While the
+	// spec hash is unchanged the Job is not created again, even after ttlSecondsAfterFinished
+	// has removed it.
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+}
+
+// AutoscalingStatusSummary is the observed state of the generated HorizontalPodAutoscaler.
+type AutoscalingStatusSummary struct {
+	Name        string `json:"name"`
+	MinReplicas int32  `json:"minReplicas"`
+	MaxReplicas int32  `json:"maxReplicas"`
+	// CurrentReplicas is the number of replicas last seen by the HPA.
+	CurrentReplicas int32 `json:"currentReplicas"`
+	// DesiredReplicas is the number of replicas the HPA last asked for.
+	DesiredReplicas int32 `json:"desiredReplicas"`
+}
+
+// ServiceStatusSummary is the observed state of the generated Service.
+type ServiceStatusSummary struct {
+	Name      string             `json:"name"`
+	Type      corev1.ServiceType `json:"type,omitempty"`
+	ClusterIP string             `json:"clusterIP,omitempty"`
+}
+
+// IngressStatusSummary is the observed state of the generated Ingress.
+type IngressStatusSummary struct {
+	Name    string `json:"name"`
+	Address string `json:"address,omitempty"`
+}
+
+// HTTPRouteStatusSummary is the observed state of the generated HTTPRoute.
+type HTTPRouteStatusSummary struct {
+	Name string `json:"name"`
+	// AcceptedParents lists the Gateways that accepted the route.
+	// +optional
+	AcceptedParents []string `json:"acceptedParents,omitempty"`
+}
+
+// PvcStatusSummary is the observed state of the generated PVC.
+type PvcStatusSummary struct {
+	Name  string                            `json:"name"`
+	Phase corev1.PersistentVolumeClaimPhase `json:"phase,omitempty"`
+}
+
 // KubeAppStatus defines the observed state of KubeApp.
 type KubeAppStatus struct {
 	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
-	Nodes []string `json:"nodes"`
+	Nodes []string `json:"nodes,omitempty"`
+
+	// ObservedGeneration is the .metadata.generation the status was computed for.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// CurrentRevision is the number of the ControllerRevision that records the current spec.
+	// +optional
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
+
+	// Conditions holds the Ready, Progressing, Degraded and ReconcileError conditions.
+	// +listType=map
+	// +listMapKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	Deployment *DeploymentStatusSummary `json:"deployment,omitempty"`
+	// Job is set instead of deployment when workloadType is Job or CronJob.
+	// +optional
+	Job        *JobStatusSummary        `json:"job,omitempty"`
+	// Autoscaling is set while spec.autoscaling generates an HPA.
+	// +optional
+	Autoscaling *AutoscalingStatusSummary `json:"autoscaling,omitempty"`
+	Service    *ServiceStatusSummary    `json:"service,omitempty"`
+	Ingress    *IngressStatusSummary    `json:"ingress,omitempty"`
+	// HTTPRoute is set while spec.routing.gateway generates an HTTPRoute.
+	// +optional
+	HTTPRoute  *HTTPRouteStatusSummary  `json:"httpRoute,omitempty"`
+	Pvc        *PvcStatusSummary        `json:"pvc,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].status`
+// +kubebuilder:printcolumn:name="Replicas",type=integer,JSONPath=`.status.deployment.readyReplicas`,description="Ready replicas of the Deployment"
+// +kubebuilder:printcolumn:name="Workload",type=string,JSONPath=`.status.deployment.kind`,priority=1
+// +kubebuilder:printcolumn:name="Last Run",type=date,JSONPath=`.status.job.lastRunTime`,priority=1
+// +kubebuilder:printcolumn:name="Desired",type=integer,JSONPath=`.status.deployment.replicas`,priority=1
+// +kubebuilder:printcolumn:name="ClusterIP",type=string,JSONPath=`.status.service.clusterIP`,priority=1
+// +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.status.ingress.address`
+// +kubebuilder:printcolumn:name="PVC",type=string,JSONPath=`.status.pvc.phase`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.status.conditions[?(@.type=="Ready")].reason`,priority=1
+// +kubebuilder:printcolumn:name="Revision",type=integer,JSONPath=`.status.currentRevision`,priority=1
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // KubeApp is the Schema for the kubeapps API.
 type KubeApp struct {
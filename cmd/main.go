@@ -39,8 +39,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	appsv1beta1 "github.com/k8s/kube-app-operator/api/v1beta1"
 	"github.com/k8s/kube-app-operator/internal/api/router"
 	"github.com/k8s/kube-app-operator/internal/controller"
+	webhookappsv1alpha1 "github.com/k8s/kube-app-operator/internal/webhook/v1alpha1"
+	webhookappsv1beta1 "github.com/k8s/kube-app-operator/internal/webhook/v1beta1"
 	"github.com/gin-contrib/cors"
 	// +kubebuilder:scaffold:imports
 	// integrated gin
@@ -62,6 +65,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(appsv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(appsv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -230,12 +234,27 @@ func main() {
 	}
 
 	if err := (&controller.KubeAppReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("kubeapp-controller"),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeApp")
 		os.Exit(1)
 	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookappsv1alpha1.SetupKubeAppWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "KubeApp")
+			os.Exit(1)
+		}
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err := webhookappsv1beta1.SetupKubeAppWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "KubeApp")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	go func() {
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
 	clustom "github.com/k8s/kube-app-operator/internal/custom"
 	"github.com/gin-gonic/gin"
 )
@@ -107,6 +108,27 @@ func GetKubeDeployments(c *gin.Context) {
 	respond(c, deployments, columns, err, "该空间下没有 Deployments")
 }
 
+// GetKubeDaemonSets
+
+func GetKubeDaemonSets(c *gin.Context) {
+	columns := []map[string]string{
+		{"label": "服务名称", "prop": "app_name"},
+		{"label": "命名空间", "prop": "namespace"},
+		{"label": "DESIRED", "prop": "desired"},
+		{"label": "CURRENT", "prop": "current"},
+		{"label": "READY", "prop": "ready"},
+		{"label": "UP-TO-DATE", "prop": "up_to_date"},
+		{"label": "AVAILABLE", "prop": "available"},
+		{"label": "节点选择器", "prop": "node_selector"},
+		{"label": "镜像", "prop": "image"},
+		{"label": "创建时间", "prop": "created_at"},
+		{"label": "AGE", "prop": "age"},
+	}
+	ns := c.DefaultQuery("namespace", "default")
+	daemonSets, err := clustom.ListDaemonSets(ns)
+	respond(c, daemonSets, columns, err, "该空间下没有 DaemonSets")
+}
+
 // GetKubeServices
 
 func GetKubeServices(c *gin.Context) {
@@ -188,6 +210,24 @@ func GetKubePods(c *gin.Context) {
 	respond(c, pods, columns, err, "该命名空间下没有 Pod")
 }
 
+// GetKubeEvents 查询命名空间下的 Event，可以通过 kind / name 只看某个对象（如 KubeApp、Deployment）的事件
+
+func GetKubeEvents(c *gin.Context) {
+	columns := []map[string]string{
+		{"label": "类型", "prop": "type"},
+		{"label": "原因", "prop": "reason"},
+		{"label": "对象", "prop": "object"},
+		{"label": "信息", "prop": "message"},
+		{"label": "来源", "prop": "source"},
+		{"label": "次数", "prop": "count"},
+		{"label": "最近发生", "prop": "last_seen"},
+		{"label": "AGE", "prop": "age"},
+	}
+	ns := c.DefaultQuery("namespace", "default")
+	events, err := clustom.ListEvents(ns, c.Query("kind"), c.Query("name"))
+	respond(c, events, columns, err, "该命名空间下没有匹配的 Event")
+}
+
 // RestartKubePodHandler 重启指定的 Pod（通过删除 Pod，让控制器重新创建）
 
 func RestartKubePod(c *gin.Context) {
@@ -251,12 +291,12 @@ func RolloutRestart(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respond(c, []interface{}{}, columns, fmt.Errorf("参数错误: "+err.Error()), "")
+		respond(c, []interface{}{}, columns, fmt.Errorf("参数错误: %v", err), "")
 		return
 	}
 
 	if err := clustom.RolloutRestart(req.Kind, req.Namespace, req.Name); err != nil {
-		respond(c, []interface{}{}, columns, fmt.Errorf("重启失败: "+err.Error()), "")
+		respond(c, []interface{}{}, columns, fmt.Errorf("重启失败: %v", err), "")
 		return
 	}
 
@@ -273,3 +313,58 @@ func RolloutRestart(c *gin.Context) {
 		fmt.Sprintf("%s/%s/%s 已触发滚动重启", req.Kind, req.Namespace, req.Name))
 }
 
+// PauseReconcile 暂停 KubeApp 的协调（kubeapp.io/reconcile: paused），operator 不再修改其子资源
+func PauseReconcile(c *gin.Context) {
+	setKubeAppPaused(c, appsv1alpha1.ReconcileAnnotation, true, "已暂停协调")
+}
+
+// ResumeReconcile 恢复 KubeApp 的协调
+func ResumeReconcile(c *gin.Context) {
+	setKubeAppPaused(c, appsv1alpha1.ReconcileAnnotation, false, "已恢复协调")
+}
+
+// PauseRollout 暂停 KubeApp 生成的 Deployment 的滚动更新（kubeapp.io/rollout: paused）
+func PauseRollout(c *gin.Context) {
+	setKubeAppPaused(c, appsv1alpha1.RolloutAnnotation, true, "已暂停滚动更新")
+}
+
+// ResumeRollout 恢复 Deployment 的滚动更新
+func ResumeRollout(c *gin.Context) {
+	setKubeAppPaused(c, appsv1alpha1.RolloutAnnotation, false, "已恢复滚动更新")
+}
+
+// setKubeAppPaused 设置或移除 KubeApp 的暂停注解并返回结果
+func setKubeAppPaused(c *gin.Context, annotation string, paused bool, result string) {
+	var req struct {
+		Namespace string `json:"namespace" binding:"required"` // 命名空间
+		Name      string `json:"name" binding:"required"`      // KubeApp 名称
+	}
+
+	columns := []map[string]string{
+		{"label": "命名空间", "prop": "namespace"},
+		{"label": "名称", "prop": "name"},
+		{"label": "注解", "prop": "annotation"},
+		{"label": "结果", "prop": "result"},
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respond(c, []interface{}{}, columns, fmt.Errorf("参数错误: %v", err), "")
+		return
+	}
+
+	if err := clustom.SetKubeAppPaused(req.Namespace, req.Name, annotation, paused); err != nil {
+		respond(c, []interface{}{}, columns, fmt.Errorf("操作失败: %v", err), "")
+		return
+	}
+
+	data := []map[string]string{
+		{
+			"namespace":  req.Namespace,
+			"name":       req.Name,
+			"annotation": annotation,
+			"result":     result,
+		},
+	}
+
+	respond(c, data, columns, nil, fmt.Sprintf("KubeApp %s/%s %s", req.Namespace, req.Name, result))
+}
@@ -6,10 +6,12 @@ import (
     k8sresources "github.com/k8s/kube-app-operator/internal/api/resources"
     "github.com/k8s/kube-app-operator/internal/api/templates"
     commontype "github.com/k8s/kube-app-operator/internal/api/types"
+    clustom "github.com/k8s/kube-app-operator/internal/custom"
     "github.com/gin-gonic/gin"
     "k8s.io/apimachinery/pkg/runtime"
     "net/http"
     "sigs.k8s.io/controller-runtime/pkg/client"
+    "strconv"
 )
 
 
@@ -72,7 +74,11 @@ func NewDeleteKubeAppHandler(k8sClient client.Client, scheme *runtime.Scheme) gi
         result := k8sresources.DeleteKubeAppResources(c.Request.Context(), k8sClient, scheme, req)
 
         if result.Err != nil {
-            c.JSON(http.StatusInternalServerError, commontype.ErrorResponse{
+            status := http.StatusInternalServerError
+            if result.Code < 50000 {
+                status = http.StatusBadRequest
+            }
+            c.JSON(status, commontype.ErrorResponse{
                 Code:    result.Code,
                 Message: result.ErrMsg,
                 Detail:  result.Err.Error(),
@@ -97,4 +103,77 @@ func NewDeleteKubeAppHandler(k8sClient client.Client, scheme *runtime.Scheme) gi
 }
 
 
+// NewListKubeAppRevisionsHandler 列出 KubeApp 的历史版本（ControllerRevision），每个版本带有相对上一版本的字段变化
+
+func NewListKubeAppRevisionsHandler(k8sClient client.Client) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        namespace, name := c.Param("ns"), c.Param("name")
+
+        var kubeapp kubev1alpha1.KubeApp
+        if err := k8sClient.Get(c.Request.Context(), client.ObjectKey{Namespace: namespace, Name: name}, &kubeapp); err != nil {
+            c.JSON(http.StatusNotFound, commontype.ErrorResponse{
+                Code:    40401,
+                Message: "KubeApp 不存在",
+                Detail:  err.Error(),
+            })
+            return
+        }
+
+        revisions, err := clustom.ListRevisions(c.Request.Context(), k8sClient, &kubeapp)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, commontype.ErrorResponse{
+                Code:    50001,
+                Message: "获取 KubeApp 版本失败",
+                Detail:  err.Error(),
+            })
+            return
+        }
+        infos, err := clustom.DescribeRevisions(revisions, kubeapp.Status.CurrentRevision)
+        if err != nil {
+            c.JSON(http.StatusInternalServerError, commontype.ErrorResponse{
+                Code:    50001,
+                Message: "解析 KubeApp 版本失败",
+                Detail:  err.Error(),
+            })
+            return
+        }
 
+        c.JSON(http.StatusOK, gin.H{
+            "message":         "ok",
+            "currentRevision": kubeapp.Status.CurrentRevision,
+            "revisions":       infos,
+            "total":           len(infos),
+        })
+    }
+}
+
+// NewRollbackKubeAppHandler 把 KubeApp 的 spec 恢复为指定版本，由 operator 重新下发
+
+func NewRollbackKubeAppHandler(k8sClient client.Client) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        namespace, name := c.Param("ns"), c.Param("name")
+        revision, err := strconv.ParseInt(c.Param("revision"), 10, 64)
+        if err != nil || revision <= 0 {
+            c.JSON(http.StatusBadRequest, commontype.ErrorResponse{
+                Code:    40001,
+                Message: "请求参数格式错误",
+                Detail:  fmt.Sprintf("版本号 %q 必须是正整数", c.Param("revision")),
+            })
+            return
+        }
+
+        if err := clustom.RollbackKubeApp(c.Request.Context(), k8sClient, namespace, name, revision); err != nil {
+            c.JSON(http.StatusInternalServerError, commontype.ErrorResponse{
+                Code:    50002,
+                Message: "回滚 KubeApp 失败",
+                Detail:  err.Error(),
+            })
+            return
+        }
+
+        c.JSON(http.StatusOK, gin.H{
+            "message":  fmt.Sprintf("KubeApp %s/%s 已回滚到版本 %d", namespace, name, revision),
+            "revision": revision,
+        })
+    }
+}
@@ -85,7 +85,7 @@ type UserInfoData struct {
 	Roles  []string `json:"roles"`
 	Status bool     `json:"status"`
 	Avatar string   `json:"avatar"`
-	Name string `json:"name""`
+	Name string `json:"name"`
 	Introduction string `json:"introduction"`
 }
 
@@ -3,9 +3,7 @@ package resources
 
 import (
     "context"
-    appsv1 "k8s.io/api/apps/v1"
-    corev1 "k8s.io/api/core/v1"
-    networkingv1 "k8s.io/api/networking/v1"
+    "fmt"
     apierrors "k8s.io/apimachinery/pkg/api/errors"
     "k8s.io/apimachinery/pkg/runtime"
     "k8s.io/apimachinery/pkg/types"
@@ -17,90 +15,36 @@ import (
 
 
 // DeleteKubeAppResources 封装资源删除逻辑
+// 子资源由 KubeApp 的 finalizer 按顺序回收：只删除 KubeApp 本身，finalizer 只删除归属该 KubeApp 的子资源，
+// 并按 pvc.reclaimPolicy 处理 PVC（Retain / Snapshot 不会直接删除）。
+// KubeApp 存在时单独删除的子资源会被 operator 重新创建，因此 deleteDeployment 等字段只能与 deleteKubeApp 一起使用
 
 func DeleteKubeAppResources(ctx context.Context, k8sClient client.Client, scheme *runtime.Scheme, req commontype.KubeDeleteAppRequest) commontype.DeleteResult {
     key := types.NamespacedName{Name: req.Name, Namespace: req.Namespace}
-    deleted := []string{}
-    notFound := []string{}
 
-    // Deployment
-    if req.DeleteDeployment {
-        var dep appsv1.Deployment
-        if err := k8sClient.Get(ctx, key, &dep); err == nil {
-            if err := k8sClient.Delete(ctx, &dep); err != nil {
-                return commontype.DeleteResult{Err: err, ErrMsg: "删除 Deployment 失败", Code: 50001}
+    if !req.DeleteKubeApp {
+        if req.DeleteDeployment || req.DeleteService || req.DeleteIngress || req.DeletePVC {
+            return commontype.DeleteResult{
+                Err:    fmt.Errorf("子资源由 KubeApp 的 finalizer 回收，需要同时设置 deleteKubeApp"),
+                ErrMsg: "不支持单独删除子资源",
+                Code:   40002,
             }
-            deleted = append(deleted, "Deployment")
-        } else if apierrors.IsNotFound(err) {
-            notFound = append(notFound, "Deployment")
-        } else {
-            return commontype.DeleteResult{Err: err, ErrMsg: "查询 Deployment 失败", Code: 50002}
         }
+        return commontype.DeleteResult{}
     }
 
-    // Service
-    if req.DeleteService {
-        var svc corev1.Service
-        if err := k8sClient.Get(ctx, key, &svc); err == nil {
-            if err := k8sClient.Delete(ctx, &svc); err != nil {
-                return commontype.DeleteResult{Err: err, ErrMsg: "删除 Service 失败", Code: 50003}
-            }
-            deleted = append(deleted, "Service")
-        } else if apierrors.IsNotFound(err) {
-            notFound = append(notFound, "Service")
-        } else {
-            return commontype.DeleteResult{Err: err, ErrMsg: "查询 Service 失败", Code: 50004}
+    var app kubev1alpha1.KubeApp
+    if err := k8sClient.Get(ctx, key, &app); err != nil {
+        if apierrors.IsNotFound(err) {
+            return commontype.DeleteResult{NotFound: []string{"KubeApp"}}
         }
+        return commontype.DeleteResult{Err: err, ErrMsg: "查询 KubeApp 失败", Code: 50010}
     }
-
-    // Ingress
-    if req.DeleteIngress {
-        var ing networkingv1.Ingress
-        if err := k8sClient.Get(ctx, key, &ing); err == nil {
-            if err := k8sClient.Delete(ctx, &ing); err != nil {
-                return commontype.DeleteResult{Err: err, ErrMsg: "删除 Ingress 失败", Code: 50005}
-            }
-            deleted = append(deleted, "Ingress")
-        } else if apierrors.IsNotFound(err) {
-            notFound = append(notFound, "Ingress")
-        } else {
-            return commontype.DeleteResult{Err: err, ErrMsg: "查询 Ingress 失败", Code: 50006}
-        }
-    }
-
-    // PVC
-    if req.DeletePVC {
-        var pvc corev1.PersistentVolumeClaim
-        if err := k8sClient.Get(ctx, key, &pvc); err == nil {
-            if err := k8sClient.Delete(ctx, &pvc); err != nil {
-                return commontype.DeleteResult{Err: err, ErrMsg: "删除 PVC 失败", Code: 50007}
-            }
-            deleted = append(deleted, "PVC")
-        } else if apierrors.IsNotFound(err) {
-            notFound = append(notFound, "PVC")
-        } else {
-            return commontype.DeleteResult{Err: err, ErrMsg: "查询 PVC 失败", Code: 50008}
-        }
-    }
-
-    // KubeApp
-    if req.DeleteKubeApp {
-        var app kubev1alpha1.KubeApp
-        if err := k8sClient.Get(ctx, key, &app); err == nil {
-            if err := k8sClient.Delete(ctx, &app); err != nil {
-                return commontype.DeleteResult{Err: err, ErrMsg: "删除 KubeApp 失败", Code: 50009}
-            }
-            deleted = append(deleted, "KubeApp")
-        } else if apierrors.IsNotFound(err) {
-            notFound = append(notFound, "KubeApp")
-        } else {
-            return commontype.DeleteResult{Err: err, ErrMsg: "查询 KubeApp 失败", Code: 50010}
-        }
+    if err := k8sClient.Delete(ctx, &app); err != nil && !apierrors.IsNotFound(err) {
+        return commontype.DeleteResult{Err: err, ErrMsg: "删除 KubeApp 失败", Code: 50009}
     }
 
     return commontype.DeleteResult{
-        Deleted:  deleted,
-        NotFound: notFound,
+        Deleted: []string{"KubeApp"},
     }
 }
-
@@ -35,6 +35,8 @@ func RegisterRoutes(r *gin.Engine, k8sClient client.Client, scheme *runtime.Sche
     {
         v1.POST("/apps/create", handler.NewCreateKubeAppHandler(k8sClient, scheme))
         v1.POST("/apps/delete", handler.NewDeleteKubeAppHandler(k8sClient, scheme))
+        v1.GET("/apps/:ns/:name/revisions", handler.NewListKubeAppRevisionsHandler(k8sClient))
+        v1.POST("/apps/:ns/:name/revisions/:revision/rollback", handler.NewRollbackKubeAppHandler(k8sClient))
     }
 
     // init mysql
@@ -94,12 +96,18 @@ func RegisterRoutes(r *gin.Engine, k8sClient client.Client, scheme *runtime.Sche
     {
         kubes.GET("/namespace/query",handler.ListNamespaces)
         kubes.GET("/deployment/query",handler.GetKubeDeployments)
+        kubes.GET("/daemonset/query",handler.GetKubeDaemonSets)
         kubes.POST("/rollout/restart",handler.RolloutRestart)
+        kubes.POST("/rollout/pause", handler.PauseRollout)
+        kubes.POST("/rollout/resume", handler.ResumeRollout)
+        kubes.POST("/reconcile/pause", handler.PauseReconcile)
+        kubes.POST("/reconcile/resume", handler.ResumeReconcile)
         kubes.GET("/service/query", handler.GetKubeServices)
         kubes.GET("/ingress/query", handler.GetKubeIngress)
         kubes.GET("/pvc/query", handler.GetKubePVCS)
         kubes.GET("/pod/query", handler.GetKubePods)
         kubes.POST("/pod/restart",handler.RestartKubePod)
+        kubes.GET("/event/query", handler.GetKubeEvents)
 
       //  kubes.DELETE("/:id/roles", userHandler.RemoveRoles)
     }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	kubev1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
 	repo "github.com/k8s/kube-app-operator/internal/approval/repositories"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -94,7 +95,7 @@ func BuildAppFromConfig(config map[string]interface{}, name, namespace, image st
 	enableIngress, _ := config["enableIngress"].(bool)
 	enablePvc, _ := config["enablePvc"].(bool)
 
-	replicasInt32 := int32(3)
+	replicasInt32 := custom.DefaultReplicas
 	if replicas > 0 {
 		replicasInt32 = replicas
 	}
@@ -245,18 +246,43 @@ func BuildAppFromConfig(config map[string]interface{}, name, namespace, image st
 		}
 	}
 
+	// Strategy：字段与 spec.deployment.strategy 一致，maxSurge / maxUnavailable 可写整数或百分比
+	if raw, ok := deploymentConfig["strategy"].(map[string]interface{}); ok && len(raw) > 0 {
+		strategy := &kubev1alpha1.DeploymentStrategySpec{}
+		if decodeSection("strategy", raw, strategy) {
+			deployment.Strategy = strategy
+		}
+	}
+
+	// SecurityProfile：restricted / baseline / custom，字段与 spec.deployment.securityProfile 一致
+	if raw, ok := deploymentConfig["securityProfile"].(map[string]interface{}); ok && len(raw) > 0 {
+		profile := &kubev1alpha1.SecurityProfileSpec{}
+		if decodeSection("securityProfile", raw, profile) {
+			deployment.SecurityProfile = profile
+		}
+	}
+
 	// Volumes
 	hostPathType := corev1.HostPathDirectoryOrCreate
 	if vols, ok := deploymentConfig["volumes"].([]interface{}); ok {
 		for _, v := range vols {
 			vm := v.(map[string]interface{})
-			deployment.Volumes = append(deployment.Volumes, kubev1alpha1.VolumeConfig{
-				Name: vm["name"].(string),
-				HostPath: &corev1.HostPathVolumeSource{
+			volume := kubev1alpha1.VolumeConfig{Name: vm["name"].(string)}
+			// 模板中声明的 configMaps / secrets 通过同名卷挂载
+			switch {
+			case vm["configMap"] != nil:
+				volume.ConfigMap = &corev1.ConfigMapVolumeSource{
+					LocalObjectReference: corev1.LocalObjectReference{Name: getString(vm["configMap"].(map[string]interface{}), "name")},
+				}
+			case vm["secret"] != nil:
+				volume.Secret = &corev1.SecretVolumeSource{SecretName: getString(vm["secret"].(map[string]interface{}), "secretName")}
+			default:
+				volume.HostPath = &corev1.HostPathVolumeSource{
 					Path: vm["hostPath"].(map[string]interface{})["path"].(string),
 					Type: &hostPathType,
-				},
-			})
+				}
+			}
+			deployment.Volumes = append(deployment.Volumes, volume)
 		}
 	}
 
@@ -276,12 +302,14 @@ func BuildAppFromConfig(config map[string]interface{}, name, namespace, image st
 	// -------------------------------
 	service := &kubev1alpha1.ServiceSpec{}
 	if serviceConfig != nil && len(serviceConfig) > 0 {
-		service.Name = getString(serviceConfig, "name")
-		service.Port = int32(getFloat(serviceConfig, "port"))
-		service.TargetPort = int32(getFloat(serviceConfig, "targetPort"))
-		t := getString(serviceConfig, "type")
-		if t != "" {
-			service.Type = corev1.ServiceType(t)
+		service = buildServiceSpec(serviceConfig)
+	}
+	var extraServices []kubev1alpha1.ServiceSpec
+	if items, ok := config["extraServices"].([]interface{}); ok {
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				extraServices = append(extraServices, *buildServiceSpec(m))
+			}
 		}
 	}
 
@@ -300,6 +328,47 @@ func BuildAppFromConfig(config map[string]interface{}, name, namespace, image st
 			ingress.PathType = networkingv1.PathType(pt)
 		}
 		ingress.IngressClassName = getString(ingressConfig, "ingressClassName")
+		ingress.Annotations = getStringMap(ingressConfig, "annotations")
+		// 多 host / 多 path：rules[].paths[] 指向各自的 Service 端口
+		if rules, ok := ingressConfig["rules"].([]interface{}); ok {
+			for _, item := range rules {
+				r, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				rule := kubev1alpha1.IngressRule{Host: getString(r, "host")}
+				if paths, ok := r["paths"].([]interface{}); ok {
+					for _, p := range paths {
+						if pm, ok := p.(map[string]interface{}); ok {
+							rule.Paths = append(rule.Paths, kubev1alpha1.IngressPath{
+								Path:        getString(pm, "path"),
+								PathType:    networkingv1.PathType(getString(pm, "pathType")),
+								ServiceName: getString(pm, "serviceName"),
+								ServicePort: int32(getFloat(pm, "servicePort")),
+							})
+						}
+					}
+				}
+				ingress.Rules = append(ingress.Rules, rule)
+			}
+		}
+		if tls, ok := ingressConfig["tls"].([]interface{}); ok {
+			for _, item := range tls {
+				t, ok := item.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				entry := kubev1alpha1.IngressTLS{SecretName: getString(t, "secretName")}
+				if hosts, ok := t["hosts"].([]interface{}); ok {
+					for _, h := range hosts {
+						if host, ok := h.(string); ok {
+							entry.Hosts = append(entry.Hosts, host)
+						}
+					}
+				}
+				ingress.TLS = append(ingress.TLS, entry)
+			}
+		}
 	}
 
 	// -------------------------------
@@ -340,6 +409,58 @@ func BuildAppFromConfig(config map[string]interface{}, name, namespace, image st
 		}
 	}
 
+	// -------------------------------
+	// ConfigMap / Secret：字面量 data 与模板中保存的文件
+	// -------------------------------
+	var configMaps []kubev1alpha1.ConfigMapSpec
+	if items, ok := config["configMaps"].([]interface{}); ok {
+		for _, item := range items {
+			cm := item.(map[string]interface{})
+			configMaps = append(configMaps, kubev1alpha1.ConfigMapSpec{
+				Name:  getString(cm, "name"),
+				Data:  getStringMap(cm, "data"),
+				Files: getConfigFiles(cm, "files"),
+			})
+		}
+	}
+	var secrets []kubev1alpha1.SecretSpec
+	if items, ok := config["secrets"].([]interface{}); ok {
+		for _, item := range items {
+			secret := item.(map[string]interface{})
+			secrets = append(secrets, kubev1alpha1.SecretSpec{
+				Name:       getString(secret, "name"),
+				Type:       corev1.SecretType(getString(secret, "type")),
+				StringData: getStringMap(secret, "stringData"),
+				Files:      getConfigFiles(secret, "files"),
+			})
+		}
+	}
+
+	// -------------------------------
+	// Gateway API 路由、ServiceAccount 与 NetworkPolicy：模板字段与 spec 一致，直接按 JSON 解析
+	// -------------------------------
+	var routing *kubev1alpha1.RoutingSpec
+	if raw, ok := config["routing"].(map[string]interface{}); ok && len(raw) > 0 {
+		routing = &kubev1alpha1.RoutingSpec{}
+		if !decodeSection("routing", raw, routing) {
+			routing = nil
+		}
+	}
+	var serviceAccount *kubev1alpha1.ServiceAccountSpec
+	if raw, ok := config["serviceAccount"].(map[string]interface{}); ok && len(raw) > 0 {
+		serviceAccount = &kubev1alpha1.ServiceAccountSpec{}
+		if !decodeSection("serviceAccount", raw, serviceAccount) {
+			serviceAccount = nil
+		}
+	}
+	var networkPolicy *kubev1alpha1.NetworkPolicySpec
+	if raw, ok := config["networkPolicy"].(map[string]interface{}); ok && len(raw) > 0 {
+		networkPolicy = &kubev1alpha1.NetworkPolicySpec{}
+		if !decodeSection("networkPolicy", raw, networkPolicy) {
+			networkPolicy = nil
+		}
+	}
+
 	return &kubev1alpha1.KubeApp{
 		TypeMeta: metav1.TypeMeta{
 			APIVersion: "apps.kube.com/v1alpha1",
@@ -358,12 +479,66 @@ func BuildAppFromConfig(config map[string]interface{}, name, namespace, image st
 			Service:          service,
 			Ingress:          ingress,
 			Pvc:              pvc,
+			ConfigMaps:       configMaps,
+			Secrets:          secrets,
+			ExtraServices:    extraServices,
+			Routing:          routing,
+			NetworkPolicy:    networkPolicy,
+			ServiceAccount:   serviceAccount,
 		},
 	}
 }
 
 
+// buildServiceSpec 解析模板中的 Service 配置，支持单端口 port/targetPort 和多端口 ports 两种写法
+func buildServiceSpec(serviceConfig map[string]interface{}) *kubev1alpha1.ServiceSpec {
+	service := &kubev1alpha1.ServiceSpec{
+		Name:                  getString(serviceConfig, "name"),
+		Port:                  int32(getFloat(serviceConfig, "port")),
+		TargetPort:            int32(getFloat(serviceConfig, "targetPort")),
+		Type:                  corev1.ServiceType(getString(serviceConfig, "type")),
+		SessionAffinity:       corev1.ServiceAffinity(getString(serviceConfig, "sessionAffinity")),
+		ExternalTrafficPolicy: corev1.ServiceExternalTrafficPolicy(getString(serviceConfig, "externalTrafficPolicy")),
+		Annotations:           getStringMap(serviceConfig, "annotations"),
+	}
+	service.Headless, _ = serviceConfig["headless"].(bool)
+	if ports, ok := serviceConfig["ports"].([]interface{}); ok {
+		for _, item := range ports {
+			if p, ok := item.(map[string]interface{}); ok {
+				service.Ports = append(service.Ports, kubev1alpha1.ServicePort{
+					Name:       getString(p, "name"),
+					Port:       int32(getFloat(p, "port")),
+					TargetPort: int32(getFloat(p, "targetPort")),
+					Protocol:   corev1.Protocol(getString(p, "protocol")),
+				})
+			}
+		}
+	}
+	if ranges, ok := serviceConfig["loadBalancerSourceRanges"].([]interface{}); ok {
+		for _, r := range ranges {
+			if cidr, ok := r.(string); ok {
+				service.LoadBalancerSourceRanges = append(service.LoadBalancerSourceRanges, cidr)
+			}
+		}
+	}
+	return service
+}
+
 // ---- 辅助函数 ----
+// decodeSection 把模板中与 spec 字段同构的配置段解析到 out，格式错误时打印原因并返回 false
+func decodeSection(key string, raw map[string]interface{}, out interface{}) bool {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		fmt.Printf("❌ %s 配置序列化失败: %v\n", key, err)
+		return false
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		fmt.Printf("❌ %s 配置解析失败: %v\n", key, err)
+		return false
+	}
+	return true
+}
+
 func getString(m map[string]interface{}, key string) string {
 	if v, ok := m[key].(string); ok {
 		return v
@@ -377,3 +552,31 @@ func getFloat(m map[string]interface{}, key string) float64 {
 	}
 	return 0
 }
+
+func getStringMap(m map[string]interface{}, key string) map[string]string {
+	raw, ok := m[key].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(raw))
+	for k, v := range raw {
+		if str, ok := v.(string); ok {
+			out[k] = str
+		}
+	}
+	return out
+}
+
+func getConfigFiles(m map[string]interface{}, key string) []kubev1alpha1.ConfigFile {
+	raw, ok := m[key].([]interface{})
+	if !ok {
+		return nil
+	}
+	var files []kubev1alpha1.ConfigFile
+	for _, item := range raw {
+		if f, ok := item.(map[string]interface{}); ok {
+			files = append(files, kubev1alpha1.ConfigFile{Name: getString(f, "name"), Content: getString(f, "content")})
+		}
+	}
+	return files
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// applyConflictError 表示 server-side apply 时字段被其他 field manager 占用
+type applyConflictError struct {
+	kind   string
+	name   string
+	causes []string
+}
+
+func (e *applyConflictError) Error() string {
+	return fmt.Sprintf("%s %s 存在字段冲突: %s", e.kind, e.name, strings.Join(e.causes, "; "))
+}
+
+// message 返回写入 FieldConflict 条件的说明
+func (e *applyConflictError) message() string {
+	return fmt.Sprintf("%s %s: %s", e.kind, e.name, strings.Join(e.causes, "; "))
+}
+
+// selfHealDisabled 判断 KubeApp 是否通过 kubeapp.io/self-heal=disabled 关闭了自愈
+func selfHealDisabled(kubeapp *appsv1alpha1.KubeApp) bool {
+	return kubeapp.Annotations[appsv1alpha1.SelfHealAnnotation] == "disabled"
+}
+
+// conflictTrackerKey 是 context 中记录本次调和强制接管的字段冲突的 key
+type conflictTrackerKey struct{}
+
+// withConflictTracker 为一次调和创建字段冲突记录，apply 强制接管冲突字段后写入其中，
+// updateStatus 据此把 FieldConflict 条件置为 True
+func withConflictTracker(ctx context.Context) context.Context {
+	return context.WithValue(ctx, conflictTrackerKey{}, &[]*applyConflictError{})
+}
+
+// overriddenConflicts 返回本次调和中被强制接管的字段冲突
+func overriddenConflicts(ctx context.Context) []*applyConflictError {
+	if tracked, ok := ctx.Value(conflictTrackerKey{}).(*[]*applyConflictError); ok {
+		return *tracked
+	}
+	return nil
+}
+
+// apply 以 server-side apply 提交子资源，obj 中只包含 operator 负责的字段，
+// HPA、服务网格、kubectl rollout restart 等写入的其他字段不会被覆盖。
+// 先不带 force 提交以发现被其他 field manager 占用的字段，冲突记录为 FieldConflict 事件；
+// 默认随后强制接管冲突字段以回滚漂移，关闭自愈时不强制，冲突以 applyConflictError 返回并写入 status。
+// 新建或修改了子资源时记录 ChildCreated / ChildUpdated 事件
+func (r *KubeAppReconciler) apply(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obj client.Object) error {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	previousVersion, err := r.childResourceVersion(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	err = r.Patch(ctx, obj, client.Apply, client.FieldOwner(custom.FieldManager))
+	if errors.IsConflict(err) {
+		conflict := newApplyConflictError(kind, obj.GetName(), err)
+		observeChildReconcile(kubeapp, kind, "apply", childConflict)
+		r.event(kubeapp, corev1.EventTypeWarning, "FieldConflict", "Fields of %s %s are owned by another manager: %s",
+			kind, obj.GetName(), strings.Join(conflict.causes, "; "))
+		if selfHealDisabled(kubeapp) {
+			log_controller.Info("子资源字段被其他管理者占用，未强制覆盖", "类型", kind, "名称", obj.GetName(), "冲突", conflict.causes)
+			return conflict
+		}
+
+		log_controller.Info("子资源字段被其他管理者占用，强制接管", "类型", kind, "名称", obj.GetName(), "冲突", conflict.causes)
+		if tracked, ok := ctx.Value(conflictTrackerKey{}).(*[]*applyConflictError); ok {
+			*tracked = append(*tracked, conflict)
+		}
+		err = r.Patch(ctx, obj, client.Apply, client.FieldOwner(custom.FieldManager), client.ForceOwnership)
+	}
+	if err != nil {
+		observeChildReconcile(kubeapp, kind, "apply", childFailed)
+		// 未安装的可选 CRD 由调用方记录更具体的事件
+		if !meta.IsNoMatchError(err) {
+			r.event(kubeapp, corev1.EventTypeWarning, "ChildApplyFailed", "Failed to apply %s %s: %v", kind, obj.GetName(), err)
+		}
+		return err
+	}
+	r.recordApplied(kubeapp, kind, obj, previousVersion)
+	return nil
+}
+
+// newApplyConflictError 从 apply 返回的 409 中取出冲突的 field manager 和字段
+func newApplyConflictError(kind, name string, err error) *applyConflictError {
+	conflict := &applyConflictError{kind: kind, name: name}
+	if status, ok := err.(errors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				conflict.causes = append(conflict.causes, cause.Message)
+			}
+		}
+	}
+	if len(conflict.causes) == 0 {
+		conflict.causes = []string{err.Error()}
+	}
+	return conflict
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// replicasHandoffFieldManager 在启用 autoscaling 时临时持有工作负载的 spec.replicas。
+// operator 停止声明一个只有它自己拥有的字段时，API server 会删除该字段并回到默认的 1 个副本；
+// 先由这个管理者以当前值共同持有，operator 再放手，副本数就保持不变，之后 HPA 的写入会接管该字段
+const replicasHandoffFieldManager = custom.FieldManager + "-replicas-handoff"
+
+// reconcileAutoscaling 按 spec.autoscaling 下发或删除 HPA
+func (r *KubeAppReconciler) reconcileAutoscaling(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	if !custom.AutoscalingEnabled(kubeapp) {
+		return custom.DeleteHorizontalPodAutoscaler(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+
+	hpa, err := custom.NewHorizontalPodAutoscaler(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, hpa, r.Scheme); err != nil {
+		return err
+	}
+	return r.apply(ctx, kubeapp, hpa)
+}
+
+// handOverReplicas 在 operator 不再声明 spec.replicas 之前，把当前副本数交给 replicasHandoffFieldManager 共同持有。
+// desired 是即将 apply 的 Deployment / StatefulSet；对象不存在或 operator 已不持有该字段时什么也不做
+func (r *KubeAppReconciler) handOverReplicas(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, desired client.Object) error {
+	gvk := desired.GetObjectKind().GroupVersionKind()
+	live := &unstructured.Unstructured{}
+	live.SetGroupVersionKind(gvk)
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), live); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !ownsField(live.GetManagedFields(), custom.FieldManager, "spec", "replicas") {
+		return nil
+	}
+	replicas, found, err := unstructured.NestedInt64(live.Object, "spec", "replicas")
+	if err != nil || !found {
+		return err
+	}
+
+	handoff := &unstructured.Unstructured{}
+	handoff.SetGroupVersionKind(gvk)
+	handoff.SetName(live.GetName())
+	handoff.SetNamespace(live.GetNamespace())
+	if err := unstructured.SetNestedField(handoff.Object, replicas, "spec", "replicas"); err != nil {
+		return err
+	}
+	if err := r.Patch(ctx, handoff, client.Apply, client.FieldOwner(replicasHandoffFieldManager)); err != nil {
+		return err
+	}
+
+	log_controller.Info("已启用 autoscaling，spec.replicas 交给 HPA 管理", "类型", gvk.Kind, "名称", live.GetName(), "副本数", replicas)
+	r.event(kubeapp, corev1.EventTypeNormal, "ReplicasHandedOver",
+		"Stopped managing the replicas of %s %s (currently %d) in favour of the HorizontalPodAutoscaler", gvk.Kind, live.GetName(), replicas)
+	return nil
+}
+
+// ownsField 判断 manager 以 Apply 方式提交的 managedFields 中是否包含 path 指向的字段
+func ownsField(managedFields []metav1.ManagedFieldsEntry, manager string, path ...string) bool {
+	for _, entry := range managedFields {
+		if entry.Manager != manager || entry.Operation != metav1.ManagedFieldsOperationApply || entry.FieldsV1 == nil {
+			continue
+		}
+		raw := entry.FieldsV1.Raw
+		found := true
+		for _, name := range path {
+			var fields map[string]json.RawMessage
+			if err := json.Unmarshal(raw, &fields); err != nil {
+				found = false
+				break
+			}
+			if raw, found = fields["f:"+name]; !found {
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileConfigs 下发 spec.configMaps / spec.secrets，并删除已从 spec 中移除的托管对象。
+// 在工作负载之前执行，新 Pod 启动时引用的配置已经存在
+func (r *KubeAppReconciler) reconcileConfigs(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	configMaps, err := custom.NewConfigMaps(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+	secrets, err := custom.NewSecrets(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+
+	desired := map[string]bool{}
+	for _, cm := range configMaps {
+		if err := ctrl.SetControllerReference(kubeapp, cm, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.apply(ctx, kubeapp, cm); err != nil {
+			return err
+		}
+		desired["ConfigMap/"+cm.Name] = true
+	}
+	for _, secret := range secrets {
+		if err := ctrl.SetControllerReference(kubeapp, secret, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.apply(ctx, kubeapp, secret); err != nil {
+			return err
+		}
+		desired["Secret/"+secret.Name] = true
+	}
+
+	var cmList corev1.ConfigMapList
+	if err := r.List(ctx, &cmList, client.InNamespace(namespace), client.MatchingLabels{"managed-by": "KubeApp-operator"}); err != nil {
+		return err
+	}
+	for i := range cmList.Items {
+		if err := r.pruneConfig(ctx, kubeapp, "ConfigMap", &cmList.Items[i], desired); err != nil {
+			return err
+		}
+	}
+	var secretList corev1.SecretList
+	if err := r.List(ctx, &secretList, client.InNamespace(namespace), client.MatchingLabels{"managed-by": "KubeApp-operator"}); err != nil {
+		return err
+	}
+	for i := range secretList.Items {
+		if err := r.pruneConfig(ctx, kubeapp, "Secret", &secretList.Items[i], desired); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pruneConfig 删除由该 KubeApp 控制、但已不在 spec 中的 ConfigMap / Secret
+func (r *KubeAppReconciler) pruneConfig(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, kind string, obj client.Object, desired map[string]bool) error {
+	if desired[kind+"/"+obj.GetName()] || !metav1.IsControlledBy(obj, kubeapp) || obj.GetDeletionTimestamp() != nil {
+		return nil
+	}
+	if err := r.Delete(ctx, obj); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	log_controller.Info("配置已从 spec 中移除，删除托管对象", "类型", kind, "名称", obj.GetName())
+	r.recordChildDeleted(kubeapp, kind, obj.GetName())
+	return nil
+}
@@ -21,24 +21,68 @@ import (
 	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
 	custom "github.com/k8s/kube-app-operator/internal/custom"
 	"k8s.io/apimachinery/pkg/api/errors"
-	// appsv1 "k8s.io/api/apps/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/utils/pointer"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
 // KubeAppReconciler reconciles a kubeapp object
 
 type KubeAppReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
 }
 
-// +kubebuilder:rbac:groups=apps.dgplus.com,resources=digiapps,verbs=get;list;watch;create;update;patch;delete
-// +kubebuilder:rbac:groups=apps.dgplus.com,resources=digiapps/status,verbs=get;update;patch
-// +kubebuilder:rbac:groups=apps.dgplus.com,resources=digiapps/finalizers,verbs=update
+// event 记录 KubeApp 上的 Kubernetes Event，未注入 Recorder 时（如单测）直接忽略
+func (r *KubeAppReconciler) event(kubeapp *appsv1alpha1.KubeApp, eventtype, reason, messageFmt string, args ...interface{}) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(kubeapp, eventtype, reason, messageFmt, args...)
+}
+
+// +kubebuilder:rbac:groups=apps.kube.com,resources=kubeapps,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps.kube.com,resources=kubeapps/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=apps.kube.com,resources=kubeapps/finalizers,verbs=update
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=batch,resources=jobs;cronjobs,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=autoscaling,resources=horizontalpodautoscalers,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=services,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=configmaps;secrets,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=serviceaccounts,verbs=get;list;watch;create;update;patch;delete
+// 不授予 escalate / bind：spec.serviceAccount.rules 只能是 operator 自身权限的子集，超出的 Role 会被 API Server 拒绝
+// +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles;rolebindings,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=core,resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=snapshot.storage.k8s.io,resources=volumesnapshots,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=core,resources=events,verbs=get;list;watch;create;patch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -65,101 +109,164 @@ func (r *KubeAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if err := r.Get(ctx, req.NamespacedName, &kubeapp); err != nil {
 		if errors.IsNotFound(err) {
 			log_controller.Info("kubeapp resource not found. Ignoring since object must be deleted.")
+			forgetKubeAppMetrics(req.Namespace, req.Name)
 			return ctrl.Result{}, nil
 		}
 		return ctrl.Result{}, nil
 	}
 
-
-	//  controller deployment resource create or delete  ture eq create  false eq delete 
-
-	if kubeapp.Spec.EnableDeployment {
-		dep, err := custom.NewDeployment(&kubeapp, req.Namespace)
-		if err != nil {
-			return ctrl.Result{}, err
-		}
-		ctrl.SetControllerReference(&kubeapp, dep, r.Scheme)
-		if err := r.createOrUpdate(ctx, dep); err != nil {
+	// 删除中的 KubeApp 交给 finalizer 按顺序回收子资源（即使已暂停协调，删除也是显式操作）
+	if !kubeapp.DeletionTimestamp.IsZero() {
+		return r.finalize(ctx, &kubeapp)
+	}
+	if controllerutil.AddFinalizer(&kubeapp, appsv1alpha1.Finalizer) {
+		if err := r.Update(ctx, &kubeapp); err != nil {
 			return ctrl.Result{}, err
 		}
-	}else{
-		if err := custom.DeleteDeployment(ctx, r.Client, &kubeapp, req.Namespace); err != nil {
-			return ctrl.Result{}, err
+	}
+
+	// 先同步子资源，再无论成功与否都把观测到的状态写回 status
+	var result ctrl.Result
+	var reconcileErr error
+	ctx = withConflictTracker(ctx)
+	outcome := reconcileSucceeded
+	if custom.ReconcilePaused(&kubeapp) {
+		log_controller.Info("KubeApp 已暂停协调，跳过子资源同步，只更新状态", "KubeApp名称", kubeapp.Name, "注解", appsv1alpha1.ReconcileAnnotation)
+		outcome = reconcilePaused
+	} else if selfHealSkipped(&kubeapp) {
+		log_controller.Info("KubeApp 已关闭自愈且 spec 未变化，跳过子资源同步，保留手动修改", "KubeApp名称", kubeapp.Name, "注解", appsv1alpha1.SelfHealAnnotation)
+		outcome = reconcileSkipped
+	} else if reconcileErr = r.validateSpec(&kubeapp); reconcileErr != nil {
+		outcome = reconcileInvalid
+	} else {
+		result, reconcileErr = r.reconcileResources(ctx, &kubeapp, req.Namespace)
+		if reconcileErr == nil {
+			reconcileErr = r.recordRevision(ctx, &kubeapp)
 		}
 	}
+	statusResult, statusErr := r.updateStatus(ctx, &kubeapp, reconcileErr)
+	if outcome == reconcileSucceeded && (reconcileErr != nil || statusErr != nil) {
+		outcome = reconcileFailed
+	}
+	observeReconcile(&kubeapp, outcome)
+	if reconcileErr != nil {
+		return ctrl.Result{}, reconcileErr
+	}
+	if statusErr != nil {
+		log_controller.Error(statusErr, "KubeApp status 更新失败", "KubeApp名称", kubeapp.Name)
+		return ctrl.Result{}, statusErr
+	}
+	if result.RequeueAfter == 0 || (statusResult.RequeueAfter > 0 && statusResult.RequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = statusResult.RequeueAfter
+	}
+	return result, nil
+}
+
+// reconcileResources 根据 spec 创建/更新/删除 ConfigMap/Secret、ServiceAccount/Role、工作负载、Service、Ingress、HTTPRoute、NetworkPolicy 和 PVC
+func (r *KubeAppReconciler) reconcileResources(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) (ctrl.Result, error) {
+
+	//  controller workload (Deployment / StatefulSet / DaemonSet / Job / CronJob) create or delete  ture eq create  false eq delete 
+
+	if err := r.reconcileConfigs(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileServiceAccount(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileWorkload(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileAutoscaling(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileDisruptionBudget(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
 	//  controller service resource create or delete  ture eq create  false eq delete
 	if kubeapp.Spec.EnableService {
-		svc, err := custom.NewService(&kubeapp, req.Namespace)
+		svc, err := custom.NewService(kubeapp, namespace)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		ctrl.SetControllerReference(&kubeapp, svc, r.Scheme)
-		if err := r.createOrUpdate(ctx, svc); err != nil {
+		if err := ctrl.SetControllerReference(kubeapp, svc, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.apply(ctx, kubeapp, svc); err != nil {
 			return ctrl.Result{}, err
 		}
 	}else {
-		if err := custom.DeleteService(ctx, r.Client, &kubeapp, req.Namespace); err != nil {
+		if err := custom.DeleteService(ctx, r.childClient(kubeapp), kubeapp, namespace); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	if err := r.reconcileExtraServices(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	//  controller ingress resource create or delete  ture eq create  false eq delete
 	if kubeapp.Spec.EnableIngress {
-		ing, err := custom.NewIngress(&kubeapp, req.Namespace)
+		ing, err := custom.NewIngress(kubeapp, namespace)
 		if err != nil {
 			return ctrl.Result{}, err
 		}
-		ctrl.SetControllerReference(&kubeapp, ing, r.Scheme)
-		if err := r.createOrUpdate(ctx, ing); err != nil {
+		if err := ctrl.SetControllerReference(kubeapp, ing, r.Scheme); err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.apply(ctx, kubeapp, ing); err != nil {
 			return ctrl.Result{}, err
 		}
 	}else {
-		if err := custom.DeleteIngress(ctx, r.Client, &kubeapp, req.Namespace); err != nil {
+		if err := custom.DeleteIngress(ctx, r.childClient(kubeapp), kubeapp, namespace); err != nil {
 			return ctrl.Result{}, err
 		}
 	}
 
+	if err := r.reconcileGatewayRoute(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileNetworkPolicy(ctx, kubeapp, namespace); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// controller pvc resource create or delete  ture eq create  false eq delete 
-	/* EnablePvc = false 且 ForceDelete = true  删除 PVC
-	   EnablePvc = false 且 ForceDelete = false 仅日志提醒
+	/* EnablePvc = false 时按 reclaimPolicy 处理（与 KubeApp 删除时的 finalizer 一致）
+	   Retain   仅日志提醒，保护数据
+	   Delete   删除 PVC（兼容旧字段 forceDelete=true）
+	   Snapshot 先创建 VolumeSnapshot，就绪后删除 PVC
 	   EnablePvc = true 创建或更新 PVC（Apply）
 	*/
-	var pvcName string
-	if kubeapp.Spec.Pvc != nil && kubeapp.Spec.Pvc.Name != "" {
-		pvcName = kubeapp.Spec.Pvc.Name
-	} else {
-		pvcName = kubeapp.Name
-	}
+	pvcName := custom.PvcName(kubeapp)
 
 	// 创建或删除 PVC
 	if !kubeapp.Spec.EnablePvc {
-		if kubeapp.Spec.Pvc != nil && kubeapp.Spec.Pvc.ForceDelete {
-			log_controller.Info("启用了 PVC 强制删除标志，开始尝试删除 PVC","PVC名称", pvcName, "命名空间", req.Namespace)
-			err := custom.DeletePvc(ctx, r.Client, &kubeapp, req.Namespace)
+		if kubeapp.Spec.Pvc != nil && kubeapp.Spec.Pvc.EffectiveReclaimPolicy() != appsv1alpha1.PvcReclaimRetain {
+			log_controller.Info("PVC 已禁用，按 reclaimPolicy 回收 PVC", "PVC名称", pvcName, "命名空间", namespace, "reclaimPolicy", kubeapp.Spec.Pvc.EffectiveReclaimPolicy())
+			done, msg, err := r.reclaimPvc(ctx, kubeapp)
 			if err != nil {
-				log_controller.Error(err, "PVC 删除失败", "PVC名称", pvcName)
+				log_controller.Error(err, "PVC 回收失败", "PVC名称", pvcName)
 				return ctrl.Result{}, err
 			}
-
-			log_controller.Info("PVC 删除成功", "PVC名称", pvcName)
+			if !done {
+				log_controller.Info("PVC 回收尚未完成", "PVC名称", pvcName, "进度", msg)
+				return ctrl.Result{RequeueAfter: teardownRequeueInterval}, nil
+			}
 		} else {
-			log_controller.Info("PVC 被禁用，但未启用强制删除。为保护数据，不执行删除操作，请管理员手动删除。","PVC名称", pvcName, "命名空间", req.Namespace)
+			log_controller.Info("PVC 被禁用，reclaimPolicy 为 Retain。为保护数据，不执行删除操作，请管理员手动删除。","PVC名称", pvcName, "命名空间", namespace)
+			if err := r.recordPvcRetained(ctx, kubeapp, namespace); err != nil {
+				return ctrl.Result{}, err
+			}
 		}
-
-		return ctrl.Result{}, nil
 	} else {
 		// 启用了 PVC，尝试创建
-		pvcObj, err := custom.NewPvc(ctx, &kubeapp, req.Namespace)
+		pvcObj, err := custom.NewPvc(ctx, kubeapp, namespace)
 		if err != nil {
 			log_controller.Error(err, "构建 PVC 对象失败", "PVC名称", pvcName)
 			return ctrl.Result{}, err
 		}
 
-		// 使用 server-side apply 方式创建 PVC
-		if err := r.Client.Patch(ctx, pvcObj, client.Apply, &client.PatchOptions{
-			FieldManager: "kubeapp-operator",
-			Force:        pointer.Bool(true),
-		}); err != nil {
+		// 与其他子资源一样以 server-side apply 下发，字段冲突同样会被检测和报告
+		if err := r.apply(ctx, kubeapp, pvcObj); err != nil {
 			log_controller.Error(err, "PVC apply 失败", "PVC名称", pvcName)
 			return ctrl.Result{}, err
 		}
@@ -169,25 +276,114 @@ func (r *KubeAppReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	return ctrl.Result{}, nil
 }
 
-func (r *KubeAppReconciler) createOrUpdate(ctx context.Context, obj client.Object) error {
-	existing := obj.DeepCopyObject().(client.Object)
-	err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing)
-	if errors.IsNotFound(err) {
-		return r.Create(ctx, obj)
-	} else if err != nil {
-		return err
+
+
+// selfHealSkipped 判断是否因 kubeapp.io/self-heal=disabled 跳过子资源同步：
+// 仅当当前 generation 已经成功同步过时跳过，spec 变化后仍会下发
+func selfHealSkipped(kubeapp *appsv1alpha1.KubeApp) bool {
+	if !selfHealDisabled(kubeapp) {
+		return false
+	}
+	return kubeapp.Status.ObservedGeneration == kubeapp.Generation &&
+		meta.IsStatusConditionFalse(kubeapp.Status.Conditions, appsv1alpha1.ConditionReconcileError) &&
+		!rolloutPauseChanged(kubeapp)
+}
+
+// rolloutPauseChanged 判断 kubeapp.io/rollout 注解与上次写入的 Paused Condition 是否不一致。
+// 注解变化不会增加 generation，关闭自愈时也需要据此重新下发 Deployment 的 spec.paused
+func rolloutPauseChanged(kubeapp *appsv1alpha1.KubeApp) bool {
+	cond := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionPaused)
+	applied := cond != nil && cond.Reason == "RolloutPaused"
+	return custom.RolloutPaused(kubeapp) != applied
+}
+
+// ignoreStatusOnlyUpdates 过滤子资源仅 status（以及 resourceVersion/managedFields）变化的更新事件
+var ignoreStatusOnlyUpdates = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectOld == nil || e.ObjectNew == nil {
+			return true
+		}
+		if e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() {
+			return true
+		}
+		oldObj, err := withoutStatus(e.ObjectOld)
+		if err != nil {
+			return true
+		}
+		newObj, err := withoutStatus(e.ObjectNew)
+		if err != nil {
+			return true
+		}
+		return !equality.Semantic.DeepEqual(oldObj, newObj)
+	},
+}
+
+// withoutStatus 转为 unstructured 并去掉与期望状态无关的字段
+func withoutStatus(obj client.Object) (map[string]interface{}, error) {
+	u, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		return nil, err
 	}
-	obj.SetResourceVersion(existing.GetResourceVersion())
-	return r.Update(ctx, obj)
+	delete(u, "status")
+	if md, ok := u["metadata"].(map[string]interface{}); ok {
+		delete(md, "resourceVersion")
+		delete(md, "managedFields")
+	}
+	return u, nil
 }
 
+// pvcToKubeApp PVC 不带 ownerReference，通过 kubeapp.io/name 标签映射回所属 KubeApp
+func pvcToKubeApp(_ context.Context, obj client.Object) []reconcile.Request {
+	name := obj.GetLabels()[appsv1alpha1.NameLabel]
+	if name == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Namespace: obj.GetNamespace(), Name: name}}}
+}
 
 // SetupWithManager sets up the controller with the Manager.
+// 监听所有子资源，被手动修改或删除时立即触发 reconcile 纠正漂移
 func (r *KubeAppReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&appsv1alpha1.KubeApp{}).
-		Named("kubeapp").
-		Complete(r)
+		Owns(&appsv1.Deployment{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&appsv1.StatefulSet{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&appsv1.DaemonSet{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		// Job / CronJob 的 status 变化不频繁，且 status.job 的最近运行时间依赖它，不过滤
+		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
+		Owns(&autoscalingv2.HorizontalPodAutoscaler{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&policyv1.PodDisruptionBudget{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&corev1.Service{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&corev1.ConfigMap{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&corev1.Secret{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&corev1.ServiceAccount{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&rbacv1.Role{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&rbacv1.RoleBinding{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&networkingv1.Ingress{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		Owns(&networkingv1.NetworkPolicy{}, builder.WithPredicates(ignoreStatusOnlyUpdates)).
+		// networkPolicy 通过名称引用其他 KubeApp，被引用方 spec 变化时重新生成引用方的策略
+		Watches(&appsv1alpha1.KubeApp{},
+			handler.EnqueueRequestsFromMapFunc(r.networkPolicyDependents),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		// 命名空间的 Pod Security Admission 标签变化时重新计算 PodSecurity Condition
+		Watches(&corev1.Namespace{},
+			handler.EnqueueRequestsFromMapFunc(r.namespaceKubeApps),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		Watches(&corev1.PersistentVolumeClaim{},
+			handler.EnqueueRequestsFromMapFunc(pvcToKubeApp),
+			builder.WithPredicates(ignoreStatusOnlyUpdates))
+
+	// Gateway API 是可选依赖：未安装 CRD 时 watch 无法启动，只在集群提供 HTTPRoute 时监听
+	if _, err := mgr.GetRESTMapper().RESTMapping(custom.HTTPRouteGVK.GroupKind(), custom.HTTPRouteGVK.Version); err == nil {
+		route := &unstructured.Unstructured{}
+		route.SetGroupVersionKind(custom.HTTPRouteGVK)
+		b = b.Owns(route, builder.WithPredicates(ignoreStatusOnlyUpdates))
+	} else {
+		log_controller.Info("集群未安装 Gateway API，不监听 HTTPRoute", "原因", err.Error())
+	}
+
+	return b.Named("kubeapp").Complete(r)
 }
 
 
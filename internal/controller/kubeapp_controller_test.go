@@ -18,18 +18,62 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
 )
 
+// deleteKubeApp 删除 KubeApp 并反复调和，直到 finalizer 回收完子资源、对象真正消失
+func deleteKubeApp(ctx context.Context, r *KubeAppReconciler, key types.NamespacedName) {
+	resource := &appsv1alpha1.KubeApp{}
+	Expect(k8sClient.Get(ctx, key, resource)).To(Succeed())
+	Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	Eventually(func(g Gomega) {
+		_, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(errors.IsNotFound(k8sClient.Get(ctx, key, &appsv1alpha1.KubeApp{}))).To(BeTrue())
+	}).Should(Succeed())
+}
+
+// recordedEvents 取出 FakeRecorder 中已经记录的全部事件
+func recordedEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case e := <-recorder.Events:
+			events = append(events, e)
+		default:
+			return events
+		}
+	}
+}
+
 var _ = Describe("KubeApp Controller", func() {
 	Context("When reconciling a resource", func() {
 		const resourceName = "test-resource"
@@ -58,13 +102,8 @@ var _ = Describe("KubeApp Controller", func() {
 		})
 
 		AfterEach(func() {
-			// TODO(user): Cleanup logic after each test, like removing the resource instance.
-			resource := &appsv1alpha1.KubeApp{}
-			err := k8sClient.Get(ctx, typeNamespacedName, resource)
-			Expect(err).NotTo(HaveOccurred())
-
 			By("Cleanup the specific resource instance KubeApp")
-			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
 		})
 		It("should successfully reconcile the resource", func() {
 			By("Reconciling the created resource")
@@ -81,4 +120,1984 @@ var _ = Describe("KubeApp Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When reporting status", func() {
+		const resourceName = "status-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		BeforeEach(func() {
+			By("creating a KubeApp with a Deployment and a Service")
+			replicas := int32(2)
+			resource := &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+				},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					EnableService:    true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+					Service: &appsv1alpha1.ServiceSpec{
+						Name:       resourceName,
+						Port:       80,
+						TargetPort: 80,
+					},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should write conditions, observedGeneration and child summaries", func() {
+			controllerReconciler := &KubeAppReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: typeNamespacedName,
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("requeueing while the Deployment has no ready replicas")
+			Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.ObservedGeneration).To(Equal(kubeapp.Generation))
+
+			Expect(kubeapp.Status.Deployment).NotTo(BeNil())
+			Expect(kubeapp.Status.Deployment.Replicas).To(Equal(int32(2)))
+			Expect(kubeapp.Status.Service).NotTo(BeNil())
+			Expect(kubeapp.Status.Service.ClusterIP).NotTo(BeEmpty())
+			Expect(kubeapp.Status.Ingress).To(BeNil())
+			Expect(kubeapp.Status.Pvc).To(BeNil())
+
+			Expect(meta.IsStatusConditionFalse(kubeapp.Status.Conditions, appsv1alpha1.ConditionReconcileError)).To(BeTrue())
+			Expect(meta.IsStatusConditionTrue(kubeapp.Status.Conditions, appsv1alpha1.ConditionProgressing)).To(BeTrue())
+			Expect(meta.IsStatusConditionFalse(kubeapp.Status.Conditions, appsv1alpha1.ConditionDegraded)).To(BeTrue())
+			ready := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(ready.Reason).To(Equal("Progressing"))
+		})
+	})
+
+	Context("When a child resource drifts", func() {
+		const resourceName = "drift-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		newKubeApp := func(annotations map[string]string) *appsv1alpha1.KubeApp {
+			replicas := int32(2)
+			return &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        resourceName,
+					Namespace:   "default",
+					Annotations: annotations,
+				},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+				},
+			}
+		}
+
+		scaleDeploymentTo := func(replicas int32) {
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			dep.Spec.Replicas = &replicas
+			Expect(k8sClient.Update(ctx, dep)).To(Succeed())
+		}
+
+		deploymentReplicas := func() int32 {
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			return *dep.Spec.Replicas
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should revert a manual edit by default", func() {
+			Expect(k8sClient.Create(ctx, newKubeApp(nil))).To(Succeed())
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			scaleDeploymentTo(5)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deploymentReplicas()).To(Equal(int32(2)))
+		})
+
+		It("should keep a manual edit when self-heal is disabled", func() {
+			Expect(k8sClient.Create(ctx, newKubeApp(map[string]string{
+				appsv1alpha1.SelfHealAnnotation: "disabled",
+			}))).To(Succeed())
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			scaleDeploymentTo(5)
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(deploymentReplicas()).To(Equal(int32(5)))
+		})
+	})
+
+	Context("When applying child resources", func() {
+		const resourceName = "apply-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		createKubeApp := func(annotations map[string]string) {
+			replicas := int32(2)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default", Annotations: annotations},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+				},
+			})).To(Succeed())
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should keep fields it does not own, such as the restartedAt annotation", func() {
+			createKubeApp(nil)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			dep.Spec.Template.Annotations = map[string]string{"kubectl.kubernetes.io/restartedAt": "2025-01-01T00:00:00Z"}
+			Expect(k8sClient.Update(ctx, dep)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Template.Annotations).To(HaveKey("kubectl.kubernetes.io/restartedAt"))
+		})
+
+		It("should report a field conflict instead of overwriting when self-heal is disabled", func() {
+			createKubeApp(map[string]string{appsv1alpha1.SelfHealAnnotation: "disabled"})
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("letting another manager take over spec.replicas")
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			manual := int32(5)
+			dep.Spec.Replicas = &manual
+			Expect(k8sClient.Update(ctx, dep, client.FieldOwner("someone-else"))).To(Succeed())
+
+			By("changing the desired replicas on the KubeApp")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			desired := int32(3)
+			kubeapp.Spec.Deployment.Replicas = &desired
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(*dep.Spec.Replicas).To(Equal(int32(5)))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			conflict := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionFieldConflict)
+			Expect(conflict).NotTo(BeNil())
+			Expect(conflict.Status).To(Equal(metav1.ConditionTrue))
+			Expect(conflict.Message).To(ContainSubstring("someone-else"))
+		})
+
+		It("should report and then take over conflicting fields when self-heal is enabled", func() {
+			createKubeApp(nil)
+			recorder := record.NewFakeRecorder(64)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("letting another manager take over spec.replicas")
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			manual := int32(5)
+			dep.Spec.Replicas = &manual
+			Expect(k8sClient.Update(ctx, dep, client.FieldOwner("someone-else"))).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(*dep.Spec.Replicas).To(Equal(int32(2)))
+			Expect(recordedEvents(recorder)).To(ContainElement(And(HavePrefix("Warning FieldConflict"), ContainSubstring("someone-else"))))
+			Expect(testutil.ToFloat64(childReconcileTotal.WithLabelValues("default", resourceName, "Deployment", "apply", childConflict))).To(Equal(1.0))
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			conflict := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionFieldConflict)
+			Expect(conflict).NotTo(BeNil())
+			Expect(conflict.Status).To(Equal(metav1.ConditionTrue))
+			Expect(conflict.Reason).To(Equal("ConflictOverridden"))
+
+			By("reconciling again after the takeover")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			conflict = meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionFieldConflict)
+			Expect(conflict.Status).To(Equal(metav1.ConditionFalse))
+		})
+	})
+
+	Context("When deleting a KubeApp", func() {
+		const resourceName = "teardown-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		createKubeApp := func(policy appsv1alpha1.PvcReclaimPolicy) {
+			replicas := int32(1)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					EnablePvc:        true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+					Pvc: &appsv1alpha1.PvcSpec{
+						Name:          resourceName,
+						Storage:       "1Gi",
+						AccessModes:   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						ReclaimPolicy: policy,
+					},
+				},
+			})).To(Succeed())
+		}
+
+		AfterEach(func() {
+			pvc := &corev1.PersistentVolumeClaim{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, pvc); err == nil {
+				pvc.Finalizers = nil
+				Expect(k8sClient.Update(ctx, pvc)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, pvc))).To(Succeed())
+			}
+		})
+
+		It("should keep the PVC with reclaimPolicy Retain", func() {
+			createKubeApp(appsv1alpha1.PvcReclaimRetain)
+			recorder := record.NewFakeRecorder(32)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Finalizers).To(ContainElement(appsv1alpha1.Finalizer))
+
+			deleteKubeApp(ctx, controllerReconciler, typeNamespacedName)
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{}))).To(BeTrue())
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pvc)).To(Succeed())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+			Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring("PvcRetained")))
+		})
+
+		It("should delete the PVC with reclaimPolicy Delete", func() {
+			createKubeApp(appsv1alpha1.PvcReclaimDelete)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			deleteKubeApp(ctx, controllerReconciler, typeNamespacedName)
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = k8sClient.Get(ctx, typeNamespacedName, pvc)
+			if err == nil {
+				// pvc-protection 的 finalizer 会让 PVC 停留在删除中
+				Expect(pvc.DeletionTimestamp).NotTo(BeNil())
+			} else {
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+
+		It("should retain the PVC when the VolumeSnapshot API is not installed", func() {
+			createKubeApp(appsv1alpha1.PvcReclaimSnapshot)
+			recorder := record.NewFakeRecorder(64)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			deleteKubeApp(ctx, controllerReconciler, typeNamespacedName)
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pvc)).To(Succeed())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+			Expect(recordedEvents(recorder)).To(ContainElement(ContainSubstring("PvcSnapshotFailed")))
+		})
+
+		It("should only trust a VolumeSnapshot taken from the current PVC", func() {
+			created := metav1.NewTime(time.Now().Truncate(time.Second))
+			pvc := &corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{
+				Name: resourceName, Namespace: "default", UID: "5f0c9a2e-0000-0000-0000-000000000001", CreationTimestamp: created,
+			}}
+			recreated := pvc.DeepCopy()
+			recreated.UID = "8b41d7c3-0000-0000-0000-000000000002"
+			Expect(custom.PvcSnapshotName(pvc)).To(Equal(resourceName + "-final-5f0c9a2e"))
+			Expect(custom.PvcSnapshotName(recreated)).NotTo(Equal(custom.PvcSnapshotName(pvc)))
+
+			snap := custom.NewPvcSnapshot(&appsv1alpha1.KubeApp{ObjectMeta: metav1.ObjectMeta{Name: resourceName}}, pvc)
+			snap.SetCreationTimestamp(metav1.NewTime(created.Add(time.Minute)))
+			Expect(snapshotTakenFrom(snap, pvc)).To(BeTrue())
+
+			By("rejecting a snapshot taken before the PVC was created")
+			snap.SetCreationTimestamp(metav1.NewTime(created.Add(-time.Hour)))
+			Expect(snapshotTakenFrom(snap, pvc)).To(BeFalse())
+
+			By("rejecting a snapshot of another PVC")
+			snap.SetCreationTimestamp(metav1.NewTime(created.Add(time.Minute)))
+			Expect(unstructured.SetNestedField(snap.Object, "other-pvc", "spec", "source", "persistentVolumeClaimName")).To(Succeed())
+			Expect(snapshotTakenFrom(snap, pvc)).To(BeFalse())
+		})
+
+		It("should not delete a same-named PVC that belongs to no KubeApp", func() {
+			Expect(k8sClient.Create(ctx, &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					Pvc: &appsv1alpha1.PvcSpec{
+						Name:          resourceName,
+						Storage:       "1Gi",
+						AccessModes:   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						ReclaimPolicy: appsv1alpha1.PvcReclaimDelete,
+					},
+				},
+			})).To(Succeed())
+			recorder := record.NewFakeRecorder(64)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			deleteKubeApp(ctx, controllerReconciler, typeNamespacedName)
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pvc)).To(Succeed())
+			Expect(pvc.DeletionTimestamp).To(BeNil())
+			Expect(recordedEvents(recorder)).To(ContainElement(HavePrefix("Warning PvcReclaimSkipped")))
+		})
+
+		It("should reclaim a PVC the operator created before the kubeapp.io/name label existed", func() {
+			legacy := &corev1.PersistentVolumeClaim{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "PersistentVolumeClaim"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+					Labels:    map[string]string{"managed-by": "KubeApp-operator"},
+				},
+				Spec: corev1.PersistentVolumeClaimSpec{
+					AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+					Resources: corev1.VolumeResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			}
+			Expect(k8sClient.Patch(ctx, legacy, client.Apply, client.FieldOwner(legacyPvcFieldManager))).To(Succeed())
+
+			kubeapp := &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					Pvc: &appsv1alpha1.PvcSpec{
+						Name:          resourceName,
+						Storage:       "1Gi",
+						AccessModes:   []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+						ReclaimPolicy: appsv1alpha1.PvcReclaimDelete,
+					},
+				},
+			}
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			done, _, err := controllerReconciler.reclaimPvc(ctx, kubeapp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(done).To(BeTrue())
+
+			pvc := &corev1.PersistentVolumeClaim{}
+			err = k8sClient.Get(ctx, typeNamespacedName, pvc)
+			if err == nil {
+				Expect(pvc.DeletionTimestamp).NotTo(BeNil())
+			} else {
+				Expect(errors.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	})
+
+	Context("When choosing a rollout strategy", func() {
+		const resourceName = "strategy-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should default to a 25% RollingUpdate and switch to Recreate", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+			Expect(*dep.Spec.Strategy.RollingUpdate.MaxSurge).To(Equal(intstr.FromString("25%")))
+			Expect(*dep.Spec.Strategy.RollingUpdate.MaxUnavailable).To(Equal(intstr.FromString("25%")))
+
+			By("switching to Recreate with rollout tuning")
+			progressDeadline, revisionHistory := int32(120), int32(3)
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.Deployment.Strategy = &appsv1alpha1.DeploymentStrategySpec{
+				Type:                    appsv1.RecreateDeploymentStrategyType,
+				MinReadySeconds:         10,
+				ProgressDeadlineSeconds: &progressDeadline,
+				RevisionHistoryLimit:    &revisionHistory,
+			}
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Strategy.Type).To(Equal(appsv1.RecreateDeploymentStrategyType))
+			Expect(dep.Spec.Strategy.RollingUpdate).To(BeNil())
+			Expect(dep.Spec.MinReadySeconds).To(Equal(int32(10)))
+			Expect(*dep.Spec.ProgressDeadlineSeconds).To(Equal(progressDeadline))
+			Expect(*dep.Spec.RevisionHistoryLimit).To(Equal(revisionHistory))
+		})
+	})
+
+	Context("When the namespace enforces a Pod Security level", func() {
+		const resourceName = "psa-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "psa-restricted",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should report the mismatch and apply the restricted profile", func() {
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "psa-restricted",
+				Labels: map[string]string{"pod-security.kubernetes.io/enforce": "restricted"},
+			}})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "psa-restricted"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Sidecars: []appsv1alpha1.ContainerSpec{{Name: "proxy", Image: "envoy:1.30"}},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			cond := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionPodSecurity)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("EnforcementMismatch"))
+			Expect(cond.Message).To(ContainSubstring(`container "proxy" must set allowPrivilegeEscalation=false`))
+
+			By("selecting the restricted profile")
+			kubeapp.Spec.Deployment.SecurityProfile = &appsv1alpha1.SecurityProfileSpec{Type: appsv1alpha1.SecurityProfileRestricted}
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			podSC := dep.Spec.Template.Spec.SecurityContext
+			Expect(*podSC.RunAsNonRoot).To(BeTrue())
+			Expect(podSC.SeccompProfile.Type).To(Equal(corev1.SeccompProfileTypeRuntimeDefault))
+			for _, c := range dep.Spec.Template.Spec.Containers {
+				Expect(*c.SecurityContext.AllowPrivilegeEscalation).To(BeFalse())
+				Expect(c.SecurityContext.Capabilities.Drop).To(ConsistOf(corev1.Capability("ALL")))
+			}
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			cond = meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionPodSecurity)
+			Expect(cond.Status).To(Equal(metav1.ConditionTrue))
+			Expect(cond.Reason).To(Equal("Compliant"))
+		})
+	})
+
+	Context("When pausing a KubeApp", func() {
+		const resourceName = "paused-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should skip child mutations while reconciliation is paused and pause only the rollout on request", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        resourceName,
+					Namespace:   "default",
+					Annotations: map[string]string{appsv1alpha1.ReconcileAnnotation: appsv1alpha1.PausedValue},
+				},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{}))).To(BeTrue())
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			cond := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionPaused)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Reason).To(Equal("ReconcilePaused"))
+			Expect(kubeapp.Status.ObservedGeneration).To(BeZero())
+
+			By("pausing only the rollout")
+			kubeapp.Annotations = map[string]string{appsv1alpha1.RolloutAnnotation: appsv1alpha1.PausedValue}
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Paused).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			cond = meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionPaused)
+			Expect(cond.Reason).To(Equal("RolloutPaused"))
+
+			By("resuming the rollout")
+			kubeapp.Annotations = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Paused).To(BeFalse())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionPaused)).To(BeNil())
+		})
+	})
+
+	Context("When recording revisions", func() {
+		const resourceName = "revision-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should number every applied spec and reuse the revision on rollback", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.26"},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			reconcileOnce := func() {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			reconcileOnce()
+
+			By("changing the image")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.Deployment.Image = "nginx:1.27"
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			reconcileOnce()
+
+			Eventually(func(g Gomega) {
+				revisions, err := custom.ListRevisions(ctx, k8sClient, kubeapp)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(revisions).To(HaveLen(2))
+				infos, err := custom.DescribeRevisions(revisions, 2)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(infos[1].Revision).To(Equal(int64(2)))
+				g.Expect(infos[1].Changes).To(ContainElement(custom.SpecChange{Path: "deployment.image", Old: "nginx:1.26", New: "nginx:1.27"}))
+				g.Expect(revisions[0].OwnerReferences).To(HaveLen(1))
+			}).Should(Succeed())
+			// status.currentRevision 读取缓存中的版本，可能要再协调一次
+			Eventually(func(g Gomega) {
+				reconcileOnce()
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+				g.Expect(kubeapp.Status.CurrentRevision).To(Equal(int64(2)))
+			}).Should(Succeed())
+
+			By("rolling back to the first revision")
+			Expect(custom.RollbackKubeApp(ctx, k8sClient, "default", resourceName, 1)).To(Succeed())
+			reconcileOnce()
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Template.Spec.Containers[0].Image).To(Equal("nginx:1.26"))
+			Eventually(func(g Gomega) {
+				revisions, err := custom.ListRevisions(ctx, k8sClient, kubeapp)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(revisions).To(HaveLen(2))
+				g.Expect(revisions[1].Revision).To(Equal(int64(3)))
+				g.Expect(revisions[1].Labels[custom.RevisionHashLabel]).NotTo(BeEmpty())
+			}).Should(Succeed())
+		})
+
+		It("should keep secret values out of the revisions and keep the current values on rollback", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.26"},
+					Secrets: []appsv1alpha1.SecretSpec{{
+						Name:       resourceName + "-secret",
+						StringData: map[string]string{"password": "s3cret"},
+						Files:      []appsv1alpha1.ConfigFile{{Name: "tls.key", Content: "private-key"}},
+					}},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			reconcileOnce := func() {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+			reconcileOnce()
+
+			By("changing the image and the password")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.Deployment.Image = "nginx:1.27"
+			kubeapp.Spec.Secrets[0].StringData["password"] = "rotated"
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			reconcileOnce()
+
+			Eventually(func(g Gomega) {
+				revisions, err := custom.ListRevisions(ctx, k8sClient, kubeapp)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(revisions).To(HaveLen(2))
+				for _, rev := range revisions {
+					g.Expect(string(rev.Data.Raw)).NotTo(ContainSubstring("s3cret"))
+					g.Expect(string(rev.Data.Raw)).NotTo(ContainSubstring("rotated"))
+					g.Expect(string(rev.Data.Raw)).NotTo(ContainSubstring("private-key"))
+				}
+				infos, err := custom.DescribeRevisions(revisions, 2)
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(infos[1].Changes).To(ConsistOf(custom.SpecChange{Path: "deployment.image", Old: "nginx:1.26", New: "nginx:1.27"}))
+			}).Should(Succeed())
+
+			By("rolling back to the first revision")
+			Expect(custom.RollbackKubeApp(ctx, k8sClient, "default", resourceName, 1)).To(Succeed())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Spec.Deployment.Image).To(Equal("nginx:1.26"))
+			Expect(kubeapp.Spec.Secrets[0].StringData).To(Equal(map[string]string{"password": "rotated"}))
+			Expect(kubeapp.Spec.Secrets[0].Files).To(Equal([]appsv1alpha1.ConfigFile{{Name: "tls.key", Content: "private-key"}}))
+		})
+	})
+
+	Context("When two specs hash to the same revision name", func() {
+		const resourceName = "revision-collision"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should add a collision suffix when another spec already uses the revision name", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			hash, err := custom.SpecHash(kubeapp)
+			Expect(err).NotTo(HaveOccurred())
+
+			By("recording a different spec under the same hash")
+			other := kubeapp.DeepCopy()
+			other.Spec.Deployment.Image = "nginx:1.26"
+			colliding, err := custom.NewRevision(other, 1, 0)
+			Expect(err).NotTo(HaveOccurred())
+			colliding.Name = custom.RevisionName(kubeapp, hash, 0)
+			colliding.Labels[custom.RevisionHashLabel] = hash
+			Expect(controllerutil.SetControllerReference(kubeapp, colliding, k8sClient.Scheme())).To(Succeed())
+			Expect(k8sClient.Create(ctx, colliding)).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			Eventually(func(g Gomega) {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+				g.Expect(kubeapp.Status.CurrentRevision).To(Equal(int64(2)))
+			}).Should(Succeed())
+
+			revisions, err := custom.ListRevisions(ctx, k8sClient, kubeapp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revisions).To(HaveLen(2))
+			Expect(revisions[1].Name).To(Equal(custom.RevisionName(kubeapp, hash, 1)))
+			Expect(revisions[1].Labels[custom.RevisionHashLabel]).To(Equal(hash))
+			Expect(k8sClient.Delete(ctx, &revisions[0])).To(Succeed())
+		})
+	})
+
+	Context("When revisions of another KubeApp carry the same name label", func() {
+		const resourceName = "revision-foreign"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should neither match nor prune revisions it does not control", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+
+			By("leaving revisions of a deleted KubeApp with the same name and spec")
+			var stale []*appsv1.ControllerRevision
+			for i := 0; i < custom.RevisionHistoryLimit+1; i++ {
+				rev, err := custom.NewRevision(kubeapp, int64(50+i), 0)
+				Expect(err).NotTo(HaveOccurred())
+				rev.Name = fmt.Sprintf("%s-stale-%d", resourceName, i)
+				Expect(k8sClient.Create(ctx, rev)).To(Succeed())
+				stale = append(stale, rev)
+			}
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			Eventually(func(g Gomega) {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				g.Expect(err).NotTo(HaveOccurred())
+				g.Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+				g.Expect(kubeapp.Status.CurrentRevision).To(Equal(int64(1)))
+			}).Should(Succeed())
+
+			revisions, err := custom.ListRevisions(ctx, k8sClient, kubeapp)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(revisions).To(HaveLen(1))
+			Expect(metav1.IsControlledBy(&revisions[0], kubeapp)).To(BeTrue())
+			for _, rev := range stale {
+				Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(rev), &appsv1.ControllerRevision{})).To(Succeed())
+				Expect(k8sClient.Delete(ctx, rev)).To(Succeed())
+			}
+		})
+	})
+
+	Context("When running a StatefulSet workload", func() {
+		const resourceName = "sts-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create a StatefulSet with its headless Service and switch back to a Deployment", func() {
+			replicas := int32(2)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					WorkloadType:     appsv1alpha1.WorkloadStatefulSet,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:         resourceName,
+						Image:        "redis:7",
+						Replicas:     &replicas,
+						Ports:        []corev1.ContainerPort{{Name: "redis", ContainerPort: 6379}},
+						VolumeMounts: []appsv1alpha1.VolumeMount{{Name: "data", MountPath: "/data"}},
+					},
+					StatefulSet: &appsv1alpha1.StatefulSetSpec{
+						PodManagementPolicy:  appsv1.ParallelPodManagement,
+						VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplate{{Name: "data", Storage: "1Gi"}},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			sts := &appsv1.StatefulSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sts)).To(Succeed())
+			Expect(sts.Spec.ServiceName).To(Equal(resourceName + "-headless"))
+			Expect(sts.Spec.PodManagementPolicy).To(Equal(appsv1.ParallelPodManagement))
+			Expect(sts.Spec.VolumeClaimTemplates).To(HaveLen(1))
+			Expect(sts.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name).To(Equal("data"))
+
+			headless := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-headless", Namespace: "default"}, headless)).To(Succeed())
+			Expect(headless.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{}))).To(BeTrue())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.Deployment).NotTo(BeNil())
+			Expect(kubeapp.Status.Deployment.Kind).To(Equal("StatefulSet"))
+
+			By("switching the workloadType back to Deployment")
+			kubeapp.Spec.WorkloadType = appsv1alpha1.WorkloadDeployment
+			kubeapp.Spec.Deployment.VolumeMounts = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{})).To(Succeed())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.StatefulSet{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-headless", Namespace: "default"}, &corev1.Service{}))).To(BeTrue())
+		})
+
+		It("should not delete a same-named headless Service it does not control", func() {
+			headlessName := types.NamespacedName{Name: resourceName + "-headless", Namespace: "default"}
+			Expect(k8sClient.Create(ctx, &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{Name: headlessName.Name, Namespace: headlessName.Namespace},
+				Spec: corev1.ServiceSpec{
+					ClusterIP: corev1.ClusterIPNone,
+					Ports:     []corev1.ServicePort{{Name: "redis", Port: 6379}},
+				},
+			})).To(Succeed())
+
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:  resourceName,
+						Image: "redis:7",
+						Ports: []corev1.ContainerPort{{Name: "redis", ContainerPort: 6379}},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{})).To(Succeed())
+			headless := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, headlessName, headless)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, headless)).To(Succeed())
+		})
+	})
+
+	Context("When running a DaemonSet workload", func() {
+		const resourceName = "ds-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create a DaemonSet with tolerations, nodeSelector and maxUnavailable", func() {
+			maxUnavailable := intstr.FromString("10%")
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					WorkloadType:     appsv1alpha1.WorkloadDaemonSet,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:         resourceName,
+						Image:        "prom/node-exporter:v1.8.2",
+						NodeSelector: map[string]string{"kubernetes.io/os": "linux"},
+						Tolerations:  []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+					},
+					DaemonSet: &appsv1alpha1.DaemonSetSpec{MaxUnavailable: &maxUnavailable},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			ds := &appsv1.DaemonSet{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ds)).To(Succeed())
+			Expect(ds.Spec.Template.Spec.NodeSelector).To(HaveKeyWithValue("kubernetes.io/os", "linux"))
+			Expect(ds.Spec.Template.Spec.Tolerations).To(HaveLen(1))
+			Expect(ds.Spec.UpdateStrategy.Type).To(Equal(appsv1.RollingUpdateDaemonSetStrategyType))
+			Expect(*ds.Spec.UpdateStrategy.RollingUpdate.MaxUnavailable).To(Equal(maxUnavailable))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{}))).To(BeTrue())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.Deployment).NotTo(BeNil())
+			Expect(kubeapp.Status.Deployment.Kind).To(Equal("DaemonSet"))
+		})
+	})
+
+	Context("When autoscaling a Deployment", func() {
+		const resourceName = "hpa-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create an HPA and keep the current replicas when the operator lets go of them", func() {
+			replicas := int32(3)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27", Replicas: &replicas},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("enabling autoscaling")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			cpuTarget := int32(60)
+			kubeapp.Spec.Autoscaling = &appsv1alpha1.AutoscalingSpec{MaxReplicas: 6, TargetCPUUtilizationPercentage: &cpuTarget}
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, hpa)).To(Succeed())
+			Expect(hpa.Spec.ScaleTargetRef.Kind).To(Equal("Deployment"))
+			Expect(*hpa.Spec.MinReplicas).To(Equal(int32(3)))
+			Expect(hpa.Spec.MaxReplicas).To(Equal(int32(6)))
+			Expect(*hpa.Spec.Metrics[0].Resource.Target.AverageUtilization).To(Equal(int32(60)))
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(*dep.Spec.Replicas).To(Equal(int32(3)))
+			Expect(ownsField(dep.ManagedFields, custom.FieldManager, "spec", "replicas")).To(BeFalse())
+
+			By("letting another manager scale the Deployment")
+			scaled := int32(5)
+			dep.Spec.Replicas = &scaled
+			Expect(k8sClient.Update(ctx, dep)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(*dep.Spec.Replicas).To(Equal(int32(5)))
+
+			By("disabling autoscaling")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.Autoscaling = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &autoscalingv2.HorizontalPodAutoscaler{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(*dep.Spec.Replicas).To(Equal(int32(3)))
+		})
+	})
+
+	Context("When a disruption budget is requested", func() {
+		const resourceName = "pdb-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should warn instead of creating a PDB for a single replica", func() {
+			replicas := int32(1)
+			minAvailable := intstr.FromInt32(1)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27", Replicas: &replicas},
+					DisruptionBudget: &appsv1alpha1.DisruptionBudgetSpec{MinAvailable: &minAvailable},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &policyv1.PodDisruptionBudget{}))).To(BeTrue())
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			cond := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionDisruptionBudget)
+			Expect(cond).NotTo(BeNil())
+			Expect(cond.Status).To(Equal(metav1.ConditionFalse))
+			Expect(cond.Reason).To(Equal("SingleReplica"))
+
+			By("scaling the Deployment to three replicas")
+			replicas = 3
+			kubeapp.Spec.Deployment.Replicas = &replicas
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			pdb := &policyv1.PodDisruptionBudget{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, pdb)).To(Succeed())
+			Expect(pdb.Spec.Selector.MatchLabels).To(Equal(map[string]string{"app": resourceName}))
+			Expect(*pdb.Spec.MinAvailable).To(Equal(minAvailable))
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(kubeapp.Status.Conditions, appsv1alpha1.ConditionDisruptionBudget)).To(BeTrue())
+		})
+	})
+
+	Context("When declaring configMaps and secrets inline", func() {
+		const resourceName = "config-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create owned objects and roll the pod template when they change", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+					ConfigMaps: []appsv1alpha1.ConfigMapSpec{{
+						Name:  resourceName + "-config",
+						Data:  map[string]string{"LOG_LEVEL": "info"},
+						Files: []appsv1alpha1.ConfigFile{{Name: "nginx.conf", Content: "worker_processes 1;"}},
+					}},
+					Secrets: []appsv1alpha1.SecretSpec{{Name: resourceName + "-secret", StringData: map[string]string{"password": "s3cret"}}},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-config", Namespace: "default"}, cm)).To(Succeed())
+			Expect(cm.Data).To(Equal(map[string]string{"LOG_LEVEL": "info", "nginx.conf": "worker_processes 1;"}))
+			secret := &corev1.Secret{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-secret", Namespace: "default"}, secret)).To(Succeed())
+			Expect(secret.Data).To(HaveKeyWithValue("password", []byte("s3cret")))
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+			hash := deploy.Spec.Template.Annotations[appsv1alpha1.ConfigHashAnnotation]
+			Expect(hash).NotTo(BeEmpty())
+
+			By("changing the ConfigMap data and dropping the Secret")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.ConfigMaps[0].Data["LOG_LEVEL"] = "debug"
+			kubeapp.Spec.Secrets = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-config", Namespace: "default"}, cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue("LOG_LEVEL", "debug"))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-secret", Namespace: "default"}, &corev1.Secret{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+			Expect(deploy.Spec.Template.Annotations[appsv1alpha1.ConfigHashAnnotation]).NotTo(Equal(hash))
+		})
+
+		It("should not copy kubectl or operator annotations to the children", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      resourceName,
+					Namespace: "default",
+					Annotations: map[string]string{
+						"kubectl.kubernetes.io/last-applied-configuration": `{"spec":{"secrets":[{"stringData":{"password":"s3cret"}}]}}`,
+						appsv1alpha1.SelfHealAnnotation:                    "disabled",
+						"team":                                             "payments",
+					},
+				},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+					ConfigMaps:       []appsv1alpha1.ConfigMapSpec{{Name: resourceName + "-config", Data: map[string]string{"LOG_LEVEL": "info"}}},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			deploy := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, deploy)).To(Succeed())
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-config", Namespace: "default"}, cm)).To(Succeed())
+			for _, annotations := range []map[string]string{deploy.Annotations, cm.Annotations} {
+				Expect(annotations).To(HaveKeyWithValue("team", "payments"))
+				Expect(annotations).NotTo(HaveKey("kubectl.kubernetes.io/last-applied-configuration"))
+				Expect(annotations).NotTo(HaveKey(appsv1alpha1.SelfHealAnnotation))
+			}
+		})
+	})
+
+	Context("When exposing several ports and extra Services", func() {
+		const resourceName = "svc-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		metricsName := types.NamespacedName{Name: resourceName + "-metrics", Namespace: "default"}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create named ports, a headless metrics Service and remove it again", func() {
+			timeout := int32(600)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					EnableService:    true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+					Service: &appsv1alpha1.ServiceSpec{
+						Name: resourceName,
+						Ports: []appsv1alpha1.ServicePort{
+							{Name: "http", Port: 80, TargetPort: 8080},
+							{Name: "grpc", Port: 9000, TargetPort: 9000},
+						},
+						SessionAffinity:               corev1.ServiceAffinityClientIP,
+						SessionAffinityTimeoutSeconds: &timeout,
+					},
+					ExtraServices: []appsv1alpha1.ServiceSpec{{
+						Name:     metricsName.Name,
+						Headless: true,
+						Ports:    []appsv1alpha1.ServicePort{{Name: "metrics", Port: 9090, TargetPort: 9090}},
+					}},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			svc := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, svc)).To(Succeed())
+			Expect(svc.Spec.Ports).To(HaveLen(2))
+			Expect(svc.Spec.Ports[1].Name).To(Equal("grpc"))
+			Expect(svc.Spec.Ports[1].TargetPort).To(Equal(intstr.FromInt32(9000)))
+			Expect(svc.Spec.SessionAffinity).To(Equal(corev1.ServiceAffinityClientIP))
+			Expect(*svc.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds).To(Equal(timeout))
+
+			metrics := &corev1.Service{}
+			Expect(k8sClient.Get(ctx, metricsName, metrics)).To(Succeed())
+			Expect(metrics.Spec.ClusterIP).To(Equal(corev1.ClusterIPNone))
+			Expect(metrics.Spec.Selector).To(Equal(map[string]string{"app": resourceName}))
+
+			By("dropping the extra Service from the spec")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.ExtraServices = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, metricsName, &corev1.Service{}))).To(BeTrue())
+		})
+	})
+
+	Context("When routing several hosts and paths through the Ingress", func() {
+		const resourceName = "ing-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create one rule per host with TLS and the ingress annotations", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableIngress: true,
+					Ingress: &appsv1alpha1.IngressSpec{
+						Name: resourceName,
+						Rules: []appsv1alpha1.IngressRule{
+							{Host: "shop.example.com", Paths: []appsv1alpha1.IngressPath{
+								{Path: "/api", ServiceName: "shop-api", ServicePort: 8080},
+								{Path: "/static", PathType: networkingv1.PathTypeExact, ServiceName: "shop-static", ServicePort: 80},
+							}},
+							{Host: "admin.example.com", Paths: []appsv1alpha1.IngressPath{{ServiceName: "shop-admin", ServicePort: 80}}},
+						},
+						TLS:         []appsv1alpha1.IngressTLS{{Hosts: []string{"shop.example.com", "admin.example.com"}, SecretName: "shop-tls"}},
+						Annotations: map[string]string{"nginx.ingress.kubernetes.io/ssl-redirect": "true"},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			ing := &networkingv1.Ingress{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ing)).To(Succeed())
+			Expect(ing.Spec.Rules).To(HaveLen(2))
+			paths := ing.Spec.Rules[0].HTTP.Paths
+			Expect(paths).To(HaveLen(2))
+			Expect(paths[0].Backend.Service.Name).To(Equal("shop-api"))
+			Expect(paths[0].Backend.Service.Port.Number).To(Equal(int32(8080)))
+			Expect(*paths[1].PathType).To(Equal(networkingv1.PathTypeExact))
+			Expect(ing.Spec.Rules[1].HTTP.Paths[0].Path).To(Equal("/"))
+			Expect(ing.Spec.TLS).To(Equal([]networkingv1.IngressTLS{{Hosts: []string{"shop.example.com", "admin.example.com"}, SecretName: "shop-tls"}}))
+			Expect(ing.Annotations).To(HaveKeyWithValue("nginx.ingress.kubernetes.io/ssl-redirect", "true"))
+		})
+	})
+
+	Context("When routing through a Gateway API HTTPRoute", func() {
+		const resourceName = "route-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create an owned HTTPRoute with matches and weighted backends, and delete it when routing is removed", func() {
+			weight := int32(90)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					Routing: &appsv1alpha1.RoutingSpec{Gateway: &appsv1alpha1.GatewayRouteSpec{
+						ParentRefs: []appsv1alpha1.GatewayParentRef{{Name: "public", Namespace: "gateways", SectionName: "https"}},
+						Hostnames:  []string{"shop.example.com"},
+						Rules: []appsv1alpha1.HTTPRouteRule{{
+							Matches: []appsv1alpha1.HTTPRouteMatch{{
+								Path:    "/api",
+								Headers: []appsv1alpha1.HTTPHeaderMatch{{Name: "X-Canary", Value: "true"}},
+							}},
+							BackendRefs: []appsv1alpha1.HTTPBackendRef{
+								{Name: "shop-stable", Port: 8080, Weight: &weight},
+								{Name: "shop-canary", Port: 8080},
+							},
+						}},
+						Annotations: map[string]string{"team": "shop"},
+					}},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			route := &unstructured.Unstructured{}
+			route.SetGroupVersionKind(custom.HTTPRouteGVK)
+			Expect(k8sClient.Get(ctx, typeNamespacedName, route)).To(Succeed())
+			Expect(route.GetOwnerReferences()).To(HaveLen(1))
+			Expect(route.GetAnnotations()).To(HaveKeyWithValue("team", "shop"))
+
+			parents, _, _ := unstructured.NestedSlice(route.Object, "spec", "parentRefs")
+			Expect(parents).To(ConsistOf(map[string]interface{}{
+				"group": "gateway.networking.k8s.io", "kind": "Gateway", "name": "public", "namespace": "gateways", "sectionName": "https",
+			}))
+			hostnames, _, _ := unstructured.NestedStringSlice(route.Object, "spec", "hostnames")
+			Expect(hostnames).To(Equal([]string{"shop.example.com"}))
+
+			rules, _, _ := unstructured.NestedSlice(route.Object, "spec", "rules")
+			Expect(rules).To(HaveLen(1))
+			rule := rules[0].(map[string]interface{})
+			Expect(rule["matches"]).To(ConsistOf(map[string]interface{}{
+				"path":    map[string]interface{}{"type": "PathPrefix", "value": "/api"},
+				"headers": []interface{}{map[string]interface{}{"type": "Exact", "name": "X-Canary", "value": "true"}},
+			}))
+			Expect(rule["backendRefs"]).To(ConsistOf(
+				map[string]interface{}{"group": "", "kind": "Service", "name": "shop-stable", "port": int64(8080), "weight": int64(90)},
+				map[string]interface{}{"group": "", "kind": "Service", "name": "shop-canary", "port": int64(8080)},
+			))
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.HTTPRoute).NotTo(BeNil())
+			Expect(kubeapp.Status.HTTPRoute.Name).To(Equal(resourceName))
+
+			kubeapp.Spec.Routing = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, route))).To(BeTrue())
+		})
+	})
+
+	Context("When declaring network dependencies", func() {
+		const resourceName = "np-resource"
+		const backendName = "np-backend"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+		backendNamespacedName := types.NamespacedName{
+			Name:      backendName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			r := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			deleteKubeApp(ctx, r, typeNamespacedName)
+			deleteKubeApp(ctx, r, backendNamespacedName)
+		})
+
+		It("should select the pods by app label and resolve other KubeApps to their Deployment names", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: backendName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					Deployment: &appsv1alpha1.DeploymentSpec{Name: "backend-v2", Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+					NetworkPolicy: &appsv1alpha1.NetworkPolicySpec{
+						IngressFrom: &appsv1alpha1.NetworkPolicyIngress{
+							Namespaces:        []string{"monitoring"},
+							KubeApps:          []appsv1alpha1.KubeAppRef{{Name: "storefront", Namespace: "shop"}},
+							IngressController: &appsv1alpha1.IngressControllerRef{},
+						},
+						EgressTo: &appsv1alpha1.NetworkPolicyEgress{
+							KubeApps: []appsv1alpha1.KubeAppRef{{Name: backendName}},
+							CIDRs:    []networkingv1.IPBlock{{CIDR: "10.20.0.0/16", Except: []string{"10.20.1.0/24"}}},
+							DNS:      true,
+						},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			np := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, np)).To(Succeed())
+			Expect(np.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"app": resourceName}))
+			Expect(np.Spec.PolicyTypes).To(ConsistOf(networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress))
+
+			Expect(np.Spec.Ingress).To(HaveLen(1))
+			from := np.Spec.Ingress[0].From
+			Expect(from).To(HaveLen(3))
+			Expect(from[0].NamespaceSelector.MatchLabels).To(Equal(map[string]string{corev1.LabelMetadataName: "monitoring"}))
+			// 不存在的 KubeApp 按名称作为 app 标签
+			Expect(from[1].NamespaceSelector.MatchLabels).To(Equal(map[string]string{corev1.LabelMetadataName: "shop"}))
+			Expect(from[1].PodSelector.MatchLabels).To(Equal(map[string]string{"app": "storefront"}))
+			Expect(from[2].NamespaceSelector.MatchLabels).To(Equal(map[string]string{corev1.LabelMetadataName: custom.DefaultIngressControllerNamespace}))
+
+			Expect(np.Spec.Egress).To(HaveLen(2))
+			to := np.Spec.Egress[0].To
+			Expect(to).To(HaveLen(2))
+			Expect(to[0].NamespaceSelector).To(BeNil())
+			Expect(to[0].PodSelector.MatchLabels).To(Equal(map[string]string{"app": "backend-v2"}))
+			Expect(to[1].IPBlock.CIDR).To(Equal("10.20.0.0/16"))
+			Expect(np.Spec.Egress[1].Ports).To(HaveLen(2))
+			Expect(np.Spec.Egress[1].To[0].PodSelector.MatchLabels).To(Equal(map[string]string{"k8s-app": "kube-dns"}))
+
+			By("removing the networkPolicy")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.NetworkPolicy = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &networkingv1.NetworkPolicy{}))).To(BeTrue())
+		})
+
+		It("should only restrict inbound traffic when ingressFrom is set or nothing else is", func() {
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+					NetworkPolicy: &appsv1alpha1.NetworkPolicySpec{
+						EgressTo: &appsv1alpha1.NetworkPolicyEgress{DNS: true},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			np := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, np)).To(Succeed())
+			Expect(np.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeEgress}))
+			Expect(np.Spec.Ingress).To(BeEmpty())
+			Expect(np.Spec.Egress).To(HaveLen(1))
+
+			By("leaving the networkPolicy empty")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.NetworkPolicy = &appsv1alpha1.NetworkPolicySpec{}
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, np)).To(Succeed())
+			Expect(np.Spec.PolicyTypes).To(Equal([]networkingv1.PolicyType{networkingv1.PolicyTypeIngress}))
+			Expect(np.Spec.Ingress).To(BeEmpty())
+			Expect(np.Spec.Egress).To(BeEmpty())
+		})
+
+		It("should not delete a same-named NetworkPolicy it does not control", func() {
+			Expect(k8sClient.Create(ctx, &networkingv1.NetworkPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: networkingv1.NetworkPolicySpec{
+					PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{"app": resourceName}},
+					PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+				},
+			})).To(Succeed())
+
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			np := &networkingv1.NetworkPolicy{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, np)).To(Succeed())
+			Expect(np.OwnerReferences).To(BeEmpty())
+			Expect(k8sClient.Delete(ctx, np)).To(Succeed())
+		})
+	})
+
+	Context("When running the pods as a dedicated ServiceAccount", func() {
+		const resourceName = "sa-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should create the ServiceAccount, Role and RoleBinding and use them in the pod template", func() {
+			automount := false
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+					ServiceAccount: &appsv1alpha1.ServiceAccountSpec{
+						Name:                         "sa-runner",
+						Annotations:                  map[string]string{"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/sa-runner"},
+						AutomountServiceAccountToken: &automount,
+						Rules: []rbacv1.PolicyRule{{
+							APIGroups: []string{""},
+							Resources: []string{"configmaps"},
+							Verbs:     []string{"get", "list", "watch"},
+						}},
+					},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			saKey := types.NamespacedName{Name: "sa-runner", Namespace: "default"}
+			sa := &corev1.ServiceAccount{}
+			Expect(k8sClient.Get(ctx, saKey, sa)).To(Succeed())
+			Expect(sa.Annotations).To(HaveKeyWithValue("eks.amazonaws.com/role-arn", "arn:aws:iam::123456789012:role/sa-runner"))
+			Expect(*sa.AutomountServiceAccountToken).To(BeFalse())
+			Expect(sa.OwnerReferences).To(HaveLen(1))
+
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, saKey, role)).To(Succeed())
+			Expect(role.Rules).To(HaveLen(1))
+			Expect(role.Rules[0].Resources).To(Equal([]string{"configmaps"}))
+			binding := &rbacv1.RoleBinding{}
+			Expect(k8sClient.Get(ctx, saKey, binding)).To(Succeed())
+			Expect(binding.RoleRef.Name).To(Equal("sa-runner"))
+			Expect(binding.Subjects).To(Equal([]rbacv1.Subject{{Kind: rbacv1.ServiceAccountKind, Name: "sa-runner", Namespace: "default"}}))
+
+			dep := &appsv1.Deployment{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, dep)).To(Succeed())
+			Expect(dep.Spec.Template.Spec.ServiceAccountName).To(Equal("sa-runner"))
+			Expect(*dep.Spec.Template.Spec.AutomountServiceAccountToken).To(BeFalse())
+
+			By("dropping the rules")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.ServiceAccount.Rules = nil
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, saKey, &rbacv1.Role{}))).To(BeTrue())
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, saKey, &rbacv1.RoleBinding{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, saKey, &corev1.ServiceAccount{})).To(Succeed())
+		})
+
+		It("should not delete a same-named ServiceAccount and Role it does not control", func() {
+			Expect(k8sClient.Create(ctx, &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			})).To(Succeed())
+			Expect(k8sClient.Create(ctx, &rbacv1.Role{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Rules:      []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}}},
+			})).To(Succeed())
+
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "nginx:1.27"},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			sa := &corev1.ServiceAccount{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, sa)).To(Succeed())
+			role := &rbacv1.Role{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, role)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, sa)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, role)).To(Succeed())
+		})
+	})
+
+	Context("When running a Job workload", func() {
+		const resourceName = "job-resource"
+
+		ctx := context.Background()
+
+		typeNamespacedName := types.NamespacedName{
+			Name:      resourceName,
+			Namespace: "default",
+		}
+
+		AfterEach(func() {
+			deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+		})
+
+		It("should recreate the Job when its spec changes and switch to a CronJob", func() {
+			backoffLimit := int32(2)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					WorkloadType:     appsv1alpha1.WorkloadJob,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "busybox:1.36"},
+					Job:              &appsv1alpha1.JobSpec{BackoffLimit: &backoffLimit},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			job := &batchv1.Job{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, job)).To(Succeed())
+			Expect(*job.Spec.BackoffLimit).To(Equal(int32(2)))
+			Expect(job.Spec.Template.Spec.RestartPolicy).To(Equal(corev1.RestartPolicyOnFailure))
+			Expect(job.Annotations).To(HaveKey(appsv1alpha1.SpecHashAnnotation))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{}))).To(BeTrue())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.Deployment).To(BeNil())
+			Expect(kubeapp.Status.Job).NotTo(BeNil())
+			Expect(kubeapp.Status.Job.Kind).To(Equal("Job"))
+
+			By("changing the image of the Job")
+			kubeapp.Spec.Deployment.Image = "busybox:1.37"
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			replaced := &batchv1.Job{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, replaced)).To(Succeed())
+			Expect(replaced.UID).NotTo(Equal(job.UID))
+			Expect(replaced.Spec.Template.Spec.Containers[0].Image).To(Equal("busybox:1.37"))
+
+			By("switching the workloadType to CronJob")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.WorkloadType = appsv1alpha1.WorkloadCronJob
+			kubeapp.Spec.CronJob = &appsv1alpha1.CronJobSpec{Schedule: "*/10 * * * *", ConcurrencyPolicy: batchv1.ForbidConcurrent}
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			cronJob := &batchv1.CronJob{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, cronJob)).To(Succeed())
+			Expect(cronJob.Spec.Schedule).To(Equal("*/10 * * * *"))
+			Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+			Expect(*cronJob.Spec.JobTemplate.Spec.BackoffLimit).To(Equal(int32(2)))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &batchv1.Job{}))).To(BeTrue())
+		})
+
+		It("should not recreate a finished Job after ttlSecondsAfterFinished removed it", func() {
+			ttl := int32(60)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					WorkloadType:     appsv1alpha1.WorkloadJob,
+					Deployment:       &appsv1alpha1.DeploymentSpec{Name: resourceName, Image: "busybox:1.36"},
+					Job:              &appsv1alpha1.JobSpec{TTLSecondsAfterFinished: &ttl},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			By("completing the Job")
+			job := &batchv1.Job{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, job)).To(Succeed())
+			now := metav1.Now()
+			job.Status.StartTime = &now
+			job.Status.CompletionTime = &now
+			job.Status.Succeeded = 1
+			job.Status.Conditions = []batchv1.JobCondition{
+				{Type: batchv1.JobSuccessCriteriaMet, Status: corev1.ConditionTrue, LastTransitionTime: now},
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue, LastTransitionTime: now},
+			}
+			Expect(k8sClient.Status().Update(ctx, job)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.Job).NotTo(BeNil())
+			Expect(kubeapp.Status.Job.LastRunResult).To(Equal("Succeeded"))
+			Expect(kubeapp.Status.Job.SpecHash).To(Equal(job.Annotations[appsv1alpha1.SpecHashAnnotation]))
+
+			By("deleting the Job the way the TTL controller does")
+			Expect(k8sClient.Delete(ctx, job, client.PropagationPolicy(metav1.DeletePropagationBackground))).To(Succeed())
+			Eventually(func() bool {
+				return errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &batchv1.Job{}))
+			}).Should(BeTrue())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &batchv1.Job{}))).To(BeTrue())
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			Expect(kubeapp.Status.Job).NotTo(BeNil())
+			Expect(kubeapp.Status.Job.LastRunResult).To(Equal("Succeeded"))
+			Expect(kubeapp.Status.Job.LastSuccessfulTime).NotTo(BeNil())
+		})
+	})
+
+	Context("When recording events for child resources", func() {
+		const resourceName = "events-app"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+		AfterEach(func() {
+			if err := k8sClient.Get(ctx, typeNamespacedName, &appsv1alpha1.KubeApp{}); err == nil {
+				deleteKubeApp(ctx, &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}, typeNamespacedName)
+			}
+		})
+
+		It("should record create, update and delete events only when the child changes", func() {
+			replicas := int32(1)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+				},
+			})).To(Succeed())
+			recorder := record.NewFakeRecorder(64)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recordedEvents(recorder)).To(ContainElement("Normal ChildCreated Created Deployment " + resourceName))
+
+			By("reconciling again without changes")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recordedEvents(recorder)).NotTo(ContainElement(ContainSubstring("Deployment " + resourceName)))
+
+			By("changing the image")
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.Deployment.Image = "nginx:1.28"
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recordedEvents(recorder)).To(ContainElement("Normal ChildUpdated Updated Deployment " + resourceName))
+
+			By("disabling the Deployment")
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			kubeapp.Spec.EnableDeployment = false
+			Expect(k8sClient.Update(ctx, kubeapp)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recordedEvents(recorder)).To(ContainElement("Normal ChildDeleted Deleted Deployment " + resourceName))
+		})
+
+		It("should record a validation failure and skip the child resources", func() {
+			replicas := int32(1)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        resourceName,
+					Namespace:   "default",
+					Annotations: map[string]string{appsv1alpha1.RolloutAnnotation: "stopped"},
+				},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+				},
+			})).To(Succeed())
+			recorder := record.NewFakeRecorder(64)
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(HaveOccurred())
+			Expect(recordedEvents(recorder)).To(ContainElement(And(
+				HavePrefix("Warning ValidationFailed"),
+				ContainSubstring(appsv1alpha1.RolloutAnnotation),
+			)))
+			Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &appsv1.Deployment{}))).To(BeTrue())
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			ready := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Reason).To(Equal("ReconcileFailed"))
+		})
+	})
+
+	Context("When exporting metrics", func() {
+		const resourceName = "metrics-app"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+		It("should export reconcile results, conditions and replicas and forget them after deletion", func() {
+			replicas := int32(2)
+			Expect(k8sClient.Create(ctx, &appsv1alpha1.KubeApp{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: appsv1alpha1.KubeAppSpec{
+					EnableDeployment: true,
+					Deployment: &appsv1alpha1.DeploymentSpec{
+						Name:     resourceName,
+						Image:    "nginx:1.27",
+						Replicas: &replicas,
+					},
+				},
+			})).To(Succeed())
+			controllerReconciler := &KubeAppReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(testutil.ToFloat64(reconcileTotal.WithLabelValues("default", resourceName, reconcileSucceeded))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(childReconcileTotal.WithLabelValues("default", resourceName, "Deployment", "apply", childSucceeded))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(replicasDesired.WithLabelValues("default", resourceName))).To(Equal(2.0))
+			Expect(testutil.ToFloat64(replicasReady.WithLabelValues("default", resourceName))).To(Equal(0.0))
+
+			kubeapp := &appsv1alpha1.KubeApp{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, kubeapp)).To(Succeed())
+			ready := meta.FindStatusCondition(kubeapp.Status.Conditions, appsv1alpha1.ConditionReady)
+			Expect(ready).NotTo(BeNil())
+			Expect(ready.Status).To(Equal(metav1.ConditionFalse))
+			Expect(testutil.ToFloat64(statusCondition.WithLabelValues("default", resourceName, appsv1alpha1.ConditionReady, "false"))).To(Equal(1.0))
+			Expect(testutil.ToFloat64(statusCondition.WithLabelValues("default", resourceName, appsv1alpha1.ConditionReady, "true"))).To(Equal(0.0))
+			Expect(statusCondition.DeleteLabelValues("default", resourceName, appsv1alpha1.ConditionPodSecurity, "true")).To(BeFalse())
+
+			By("deleting the KubeApp")
+			deleteKubeApp(ctx, controllerReconciler, typeNamespacedName)
+			Expect(replicasDesired.DeleteLabelValues("default", resourceName)).To(BeFalse())
+			Expect(reconcileTotal.DeleteLabelValues("default", resourceName, reconcileSucceeded)).To(BeFalse())
+			Expect(statusCondition.DeleteLabelValues("default", resourceName, appsv1alpha1.ConditionReady, "false")).To(BeFalse())
+		})
+	})
+
+	Context("When filtering child events", func() {
+		It("should ignore status-only updates and pass spec changes", func() {
+			replicas := int32(1)
+			oldDep := &appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "1"},
+				Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+			}
+
+			statusOnly := oldDep.DeepCopy()
+			statusOnly.ResourceVersion = "2"
+			statusOnly.Status.ReadyReplicas = 1
+			Expect(ignoreStatusOnlyUpdates.Update(event.UpdateEvent{ObjectOld: oldDep, ObjectNew: statusOnly})).To(BeFalse())
+
+			specChange := oldDep.DeepCopy()
+			specChange.ResourceVersion = "3"
+			specChange.Spec.Template.Spec.Containers = []corev1.Container{{Name: "web", Image: "nginx:1.28"}}
+			Expect(ignoreStatusOnlyUpdates.Update(event.UpdateEvent{ObjectOld: oldDep, ObjectNew: specChange})).To(BeTrue())
+		})
+	})
 })
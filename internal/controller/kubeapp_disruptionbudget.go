@@ -0,0 +1,46 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// reconcileDisruptionBudget 按 spec.disruptionBudget 下发或删除 PDB。
+// 工作负载只有 1 个副本时不创建 PDB（否则节点驱逐会一直卡住），由 DisruptionBudget 条件提示
+func (r *KubeAppReconciler) reconcileDisruptionBudget(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	if !custom.DisruptionBudgetConfigured(kubeapp) {
+		return custom.DeletePodDisruptionBudget(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+	if replicas := custom.DisruptionBudgetReplicas(kubeapp); replicas <= 1 {
+		log_controller.Info("工作负载只有 1 个副本，跳过 PDB", "KubeApp名称", kubeapp.Name, "副本数", replicas)
+		return custom.DeletePodDisruptionBudget(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+
+	pdb, err := custom.NewPodDisruptionBudget(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, pdb, r.Scheme); err != nil {
+		return err
+	}
+	return r.apply(ctx, kubeapp, pdb)
+}
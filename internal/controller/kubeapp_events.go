@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// childEventClient 包装 client.Client，删除子资源后在 KubeApp 上记录事件，
+// 使 custom.Delete* 这类只返回 error 的删除函数也能产生 ChildDeleted 事件
+type childEventClient struct {
+	client.Client
+	r       *KubeAppReconciler
+	kubeapp *appsv1alpha1.KubeApp
+}
+
+// Delete 删除子资源，成功时记录 ChildDeleted，失败（不存在除外）时记录 ChildDeleteFailed
+func (c *childEventClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	err := c.Client.Delete(ctx, obj, opts...)
+	switch {
+	case err == nil:
+		c.r.recordChildDeleted(c.kubeapp, c.r.kindOf(obj), obj.GetName())
+	case !errors.IsNotFound(err):
+		kind := c.r.kindOf(obj)
+		observeChildReconcile(c.kubeapp, kind, "delete", childFailed)
+		c.r.event(c.kubeapp, corev1.EventTypeWarning, "ChildDeleteFailed", "Failed to delete %s %s: %v", kind, obj.GetName(), err)
+	}
+	return err
+}
+
+// recordChildDeleted 删除子资源成功后记录 ChildDeleted 事件和 kubeapp_child_reconcile_total
+func (r *KubeAppReconciler) recordChildDeleted(kubeapp *appsv1alpha1.KubeApp, kind, name string) {
+	observeChildReconcile(kubeapp, kind, "delete", childSucceeded)
+	r.event(kubeapp, corev1.EventTypeNormal, "ChildDeleted", "Deleted %s %s", kind, name)
+}
+
+// childClient 返回删除子资源时会在 kubeapp 上记录事件的 client
+func (r *KubeAppReconciler) childClient(kubeapp *appsv1alpha1.KubeApp) client.Client {
+	return &childEventClient{Client: r.Client, r: r, kubeapp: kubeapp}
+}
+
+// kindOf 返回对象的 Kind。typed 对象从 API server 读回后 TypeMeta 为空，此时从 scheme 中查找
+func (r *KubeAppReconciler) kindOf(obj client.Object) string {
+	if kind := obj.GetObjectKind().GroupVersionKind().Kind; kind != "" {
+		return kind
+	}
+	if r.Scheme != nil {
+		if gvk, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+			return gvk.Kind
+		}
+	}
+	return fmt.Sprintf("%T", obj)
+}
+
+// childResourceVersion 读取子资源当前的 resourceVersion，不存在时返回空字符串。
+// 子资源都在 Owns 的缓存中，这里不会额外请求 API server；未注册到 scheme 的类型（如 HTTPRoute）以 unstructured 读取
+func (r *KubeAppReconciler) childResourceVersion(ctx context.Context, obj client.Object) (string, error) {
+	gvk := obj.GetObjectKind().GroupVersionKind()
+	var existing client.Object
+	if r.Scheme != nil {
+		if typed, err := r.Scheme.New(gvk); err == nil {
+			existing, _ = typed.(client.Object)
+		}
+	}
+	if existing == nil {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(gvk)
+		existing = u
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), existing); err != nil {
+		// 可选的 CRD 未安装时交给后续的 apply 报错
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return existing.GetResourceVersion(), nil
+}
+
+// recordApplied 比较 apply 前后的 resourceVersion，新建时记录 ChildCreated，有变化时记录 ChildUpdated，
+// 内容未变的 apply 不会改变 resourceVersion，也就不会产生事件
+func (r *KubeAppReconciler) recordApplied(kubeapp *appsv1alpha1.KubeApp, kind string, obj client.Object, previousVersion string) {
+	observeChildReconcile(kubeapp, kind, "apply", childSucceeded)
+	switch {
+	case previousVersion == "":
+		r.event(kubeapp, corev1.EventTypeNormal, "ChildCreated", "Created %s %s", kind, obj.GetName())
+	case obj.GetResourceVersion() != previousVersion:
+		r.event(kubeapp, corev1.EventTypeNormal, "ChildUpdated", "Updated %s %s", kind, obj.GetName())
+	}
+}
+
+// validateSpec 用准入 webhook 的校验规则检查即将下发的 spec，
+// 关闭 webhook 或 webhook 上线前创建的 KubeApp 不合法时记录 ValidationFailed 事件，并且不下发子资源。
+// 校验的是下发时使用的同一个对象，不先补默认值：构建子资源时会按与 DefaultKubeApp 相同的规则处理缺省字段
+func (r *KubeAppReconciler) validateSpec(kubeapp *appsv1alpha1.KubeApp) error {
+	errs := custom.ValidateKubeApp(kubeapp)
+	if len(errs) == 0 {
+		return nil
+	}
+	log_controller.Info("KubeApp spec 验证失败，跳过子资源同步", "KubeApp名称", kubeapp.Name, "错误", errs.ToAggregate().Error())
+	r.event(kubeapp, corev1.EventTypeWarning, "ValidationFailed", "Spec is invalid: %v", errs.ToAggregate())
+	return fmt.Errorf("KubeApp spec 验证失败: %w", errs.ToAggregate())
+}
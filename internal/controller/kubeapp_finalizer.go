@@ -0,0 +1,315 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// teardownRequeueInterval 等待子资源删除完成或快照就绪的重试间隔
+const teardownRequeueInterval = 2 * time.Second
+
+// teardownChild 是 finalizer 按顺序回收的一个子资源，reason 写入 Terminating 条件
+type teardownChild struct {
+	kind   string
+	reason string
+	obj    client.Object
+}
+
+// finalize 按 HTTPRoute -> Ingress -> Service（含附加 Service） -> HPA -> PDB -> Deployment/StatefulSet/DaemonSet -> headless Service -> Job/CronJob -> NetworkPolicy -> RoleBinding/Role/ServiceAccount -> ConfigMap/Secret -> PVC 的顺序回收子资源，
+// 全部完成后移除 finalizer。StatefulSet volumeClaimTemplates 生成的 PVC 按 Kubernetes 默认策略保留
+func (r *KubeAppReconciler) finalize(ctx context.Context, kubeapp *appsv1alpha1.KubeApp) (ctrl.Result, error) {
+	if !controllerutil.ContainsFinalizer(kubeapp, appsv1alpha1.Finalizer) {
+		return ctrl.Result{}, nil
+	}
+	log_controller.Info("KubeApp 正在删除，开始按顺序回收子资源", "KubeApp名称", kubeapp.Name, "命名空间", kubeapp.Namespace)
+
+	ns := kubeapp.Namespace
+	children := []teardownChild{
+		{"HTTPRoute", "DeletingHTTPRoute", httpRouteObject(kubeapp)},
+		{"Ingress", "DeletingIngress", &networkingv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: custom.IngressName(kubeapp), Namespace: ns}}},
+		{"Service", "DeletingService", &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: custom.ServiceName(kubeapp), Namespace: ns}}},
+	}
+	// 附加 Service 与主 Service 一起先于工作负载删除
+	for _, svc := range kubeapp.Spec.ExtraServices {
+		children = append(children, teardownChild{"Service", "DeletingService", &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: svc.Name, Namespace: ns}}})
+	}
+	children = append(children, []teardownChild{
+		{"HorizontalPodAutoscaler", "DeletingHorizontalPodAutoscaler", &autoscalingv2.HorizontalPodAutoscaler{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"PodDisruptionBudget", "DeletingPodDisruptionBudget", &policyv1.PodDisruptionBudget{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"Deployment", "DeletingDeployment", &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"StatefulSet", "DeletingStatefulSet", &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"DaemonSet", "DeletingDaemonSet", &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"Service", "DeletingService", &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: custom.HeadlessServiceName(kubeapp), Namespace: ns}}},
+		{"Job", "DeletingJob", &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"CronJob", "DeletingCronJob", &batchv1.CronJob{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"NetworkPolicy", "DeletingNetworkPolicy", &networkingv1.NetworkPolicy{ObjectMeta: metav1.ObjectMeta{Name: custom.DeploymentName(kubeapp), Namespace: ns}}},
+		{"RoleBinding", "DeletingRoleBinding", &rbacv1.RoleBinding{ObjectMeta: metav1.ObjectMeta{Name: custom.ServiceAccountName(kubeapp), Namespace: ns}}},
+		{"Role", "DeletingRole", &rbacv1.Role{ObjectMeta: metav1.ObjectMeta{Name: custom.ServiceAccountName(kubeapp), Namespace: ns}}},
+		{"ServiceAccount", "DeletingServiceAccount", &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Name: custom.ServiceAccountName(kubeapp), Namespace: ns}}},
+	}...)
+	// 托管的 ConfigMap / Secret 在工作负载之后删除，Pod 退出前配置一直可用
+	for _, cm := range kubeapp.Spec.ConfigMaps {
+		children = append(children, teardownChild{"ConfigMap", "DeletingConfigMap", &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: cm.Name, Namespace: ns}}})
+	}
+	for _, secret := range kubeapp.Spec.Secrets {
+		children = append(children, teardownChild{"Secret", "DeletingSecret", &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: secret.Name, Namespace: ns}}})
+	}
+	for _, child := range children {
+		gone, err := r.deleteOwnedChild(ctx, kubeapp, child.kind, child.obj)
+		if err != nil {
+			r.event(kubeapp, corev1.EventTypeWarning, "TeardownFailed", "Failed to delete %s %s: %v", child.kind, child.obj.GetName(), err)
+			return ctrl.Result{}, err
+		}
+		if !gone {
+			return r.reportTeardown(ctx, kubeapp, child.reason,
+				fmt.Sprintf("Waiting for %s %s to be deleted", child.kind, child.obj.GetName()))
+		}
+	}
+
+	if kubeapp.Spec.Pvc != nil {
+		policy := kubeapp.Spec.Pvc.EffectiveReclaimPolicy()
+		if policy == appsv1alpha1.PvcReclaimRetain {
+			observePvcProtectionSkip(kubeapp, string(appsv1alpha1.PvcReclaimRetain))
+			r.event(kubeapp, corev1.EventTypeNormal, "PvcRetained", "Retained PVC %s per reclaimPolicy Retain", custom.PvcName(kubeapp))
+		} else {
+			done, msg, err := r.reclaimPvc(ctx, kubeapp)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			if !done {
+				return r.reportTeardown(ctx, kubeapp, "ReclaimingPvc", msg)
+			}
+		}
+	}
+
+	r.event(kubeapp, corev1.EventTypeNormal, "TeardownCompleted", "All child resources have been torn down")
+	controllerutil.RemoveFinalizer(kubeapp, appsv1alpha1.Finalizer)
+	if err := r.Update(ctx, kubeapp); err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	log_controller.Info("子资源回收完成，已移除 finalizer", "KubeApp名称", kubeapp.Name)
+	forgetKubeAppMetrics(kubeapp.Namespace, kubeapp.Name)
+	return ctrl.Result{}, nil
+}
+
+// httpRouteObject 返回用于删除 HTTPRoute 的 unstructured 对象
+func httpRouteObject(kubeapp *appsv1alpha1.KubeApp) *unstructured.Unstructured {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(custom.HTTPRouteGVK)
+	route.SetName(custom.HTTPRouteName(kubeapp))
+	route.SetNamespace(kubeapp.Namespace)
+	return route
+}
+
+// deleteOwnedChild 删除由该 KubeApp 控制的子资源，返回子资源是否已经不存在。
+// 同名但不归属该 KubeApp 的资源不会被删除
+func (r *KubeAppReconciler) deleteOwnedChild(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, kind string, obj client.Object) (bool, error) {
+	if err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		// 可选的 CRD（如 Gateway API）未安装时不可能存在该子资源
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	if !metav1.IsControlledBy(obj, kubeapp) {
+		log_controller.Info("子资源不归属该 KubeApp，跳过删除", "类型", kind, "名称", obj.GetName())
+		return true, nil
+	}
+	if obj.GetDeletionTimestamp() == nil {
+		if err := r.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil {
+			if errors.IsNotFound(err) {
+				return true, nil
+			}
+			return false, err
+		}
+		r.recordChildDeleted(kubeapp, kind, obj.GetName())
+	}
+	return false, nil
+}
+
+// reclaimPvc 按 reclaimPolicy 回收 PVC，返回是否完成以及未完成时的进度说明。
+// Retain 直接视为完成；Delete 删除 PVC；Snapshot 先创建 VolumeSnapshot，readyToUse 后再删除 PVC，
+// VolumeSnapshot API 未安装或同名快照不是当前 PVC 的快照时退化为 Retain。
+// PVC 不带 ownerReference，只回收 kubeapp.io/name 标签指向该 KubeApp 的 PVC，同名的其他 PVC 保持不动
+func (r *KubeAppReconciler) reclaimPvc(ctx context.Context, kubeapp *appsv1alpha1.KubeApp) (bool, string, error) {
+	pvcName := custom.PvcName(kubeapp)
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: pvcName}, &pvc); err != nil {
+		if errors.IsNotFound(err) {
+			return true, "", nil
+		}
+		return false, "", err
+	}
+	if !pvcOwnedBy(kubeapp, &pvc) {
+		log_controller.Info("PVC 不归属该 KubeApp，跳过回收", "PVC名称", pvcName, "KubeApp名称", kubeapp.Name)
+		if _, labelled := pvc.Labels[appsv1alpha1.NameLabel]; !labelled {
+			observePvcProtectionSkip(kubeapp, "Unlabelled")
+			r.event(kubeapp, corev1.EventTypeWarning, "PvcReclaimSkipped",
+				"PVC %s has no %s label and was not created by the operator, it is retained instead", pvcName, appsv1alpha1.NameLabel)
+		}
+		return true, "", nil
+	}
+
+	switch kubeapp.Spec.Pvc.EffectiveReclaimPolicy() {
+	case appsv1alpha1.PvcReclaimDelete:
+		return r.deletePvc(ctx, kubeapp, &pvc)
+
+	case appsv1alpha1.PvcReclaimSnapshot:
+		snap := &unstructured.Unstructured{}
+		snap.SetGroupVersionKind(custom.VolumeSnapshotGVK)
+		snapName := custom.PvcSnapshotName(&pvc)
+		err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: snapName}, snap)
+		switch {
+		case meta.IsNoMatchError(err):
+			// 无法快照时按 Retain 处理：保留数据，同时不阻塞 finalizer
+			log_controller.Info("VolumeSnapshot API 未安装，按 Retain 保留 PVC", "PVC名称", pvcName, "KubeApp名称", kubeapp.Name)
+			observePvcProtectionSkip(kubeapp, "SnapshotUnavailable")
+			r.event(kubeapp, corev1.EventTypeWarning, "PvcSnapshotFailed", "VolumeSnapshot API is not installed, PVC %s is retained instead", pvcName)
+			return true, "", nil
+		case errors.IsNotFound(err):
+			if err := r.Create(ctx, custom.NewPvcSnapshot(kubeapp, &pvc)); err != nil {
+				r.event(kubeapp, corev1.EventTypeWarning, "PvcSnapshotFailed", "Failed to create VolumeSnapshot %s: %v", snapName, err)
+				return false, "", err
+			}
+			r.event(kubeapp, corev1.EventTypeNormal, "PvcSnapshotCreated", "Created VolumeSnapshot %s of PVC %s", snapName, pvcName)
+			return false, fmt.Sprintf("Waiting for VolumeSnapshot %s to become ready", snapName), nil
+		case err != nil:
+			return false, "", err
+		}
+
+		if !snapshotTakenFrom(snap, &pvc) {
+			// 不是为当前 PVC 创建的快照不能作为备份，保留 PVC 由管理员处理
+			log_controller.Info("VolumeSnapshot 不是当前 PVC 的快照，保留 PVC", "PVC名称", pvcName, "快照名称", snapName)
+			observePvcProtectionSkip(kubeapp, "SnapshotMismatch")
+			r.event(kubeapp, corev1.EventTypeWarning, "PvcSnapshotFailed", "VolumeSnapshot %s was not taken from PVC %s, the PVC is retained instead", snapName, pvcName)
+			return true, "", nil
+		}
+		if ready, _, _ := unstructured.NestedBool(snap.Object, "status", "readyToUse"); !ready {
+			msg := fmt.Sprintf("Waiting for VolumeSnapshot %s to become ready", snapName)
+			if snapErr, found, _ := unstructured.NestedString(snap.Object, "status", "error", "message"); found && snapErr != "" {
+				msg = fmt.Sprintf("VolumeSnapshot %s failed: %s", snapName, snapErr)
+			}
+			return false, msg, nil
+		}
+		return r.deletePvc(ctx, kubeapp, &pvc)
+	}
+
+	return true, "", nil
+}
+
+// legacyPvcFieldManager 是早期版本 ApplyPvc 使用的字段管理者，那时的 PVC 只带 managed-by 标签，没有 kubeapp.io/name 标签
+const legacyPvcFieldManager = "KubeApp-operator"
+
+// pvcOwnedBy 判断 PVC 是否归属该 KubeApp：kubeapp.io/name 标签指向该 KubeApp，
+// 或者是升级前由 operator 创建、尚未补上该标签的 PVC（带 managed-by 标签且由 operator 的字段管理者写入）
+func pvcOwnedBy(kubeapp *appsv1alpha1.KubeApp, pvc *corev1.PersistentVolumeClaim) bool {
+	if name, labelled := pvc.Labels[appsv1alpha1.NameLabel]; labelled {
+		return name == kubeapp.Name
+	}
+	if pvc.Labels["managed-by"] != "KubeApp-operator" {
+		return false
+	}
+	for _, entry := range pvc.ManagedFields {
+		if entry.Manager == legacyPvcFieldManager || entry.Manager == custom.FieldManager {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotTakenFrom 判断 VolumeSnapshot 的来源是否为该 PVC，且创建时间不早于 PVC
+func snapshotTakenFrom(snap *unstructured.Unstructured, pvc *corev1.PersistentVolumeClaim) bool {
+	source, _, _ := unstructured.NestedString(snap.Object, "spec", "source", "persistentVolumeClaimName")
+	if source != pvc.Name {
+		return false
+	}
+	created := snap.GetCreationTimestamp()
+	return !created.Before(&pvc.CreationTimestamp)
+}
+
+// deletePvc 删除 PVC；pvc-protection 会等到 Pod 释放后才真正删除，这里不等待
+func (r *KubeAppReconciler) deletePvc(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, pvc *corev1.PersistentVolumeClaim) (bool, string, error) {
+	if pvc.DeletionTimestamp != nil {
+		return true, "", nil
+	}
+	if err := r.Delete(ctx, pvc); err != nil && !errors.IsNotFound(err) {
+		r.event(kubeapp, corev1.EventTypeWarning, "PvcDeleteFailed", "Failed to delete PVC %s: %v", pvc.Name, err)
+		return false, "", err
+	}
+	r.event(kubeapp, corev1.EventTypeNormal, "PvcDeleted", "Deleted PVC %s per reclaimPolicy %s", pvc.Name, kubeapp.Spec.Pvc.EffectiveReclaimPolicy())
+	return true, "", nil
+}
+
+// recordPvcRetained 关闭 enablePvc 且 reclaimPolicy 为 Retain 时，PVC 仍存在则记录 PvcRetained 事件，提醒管理员手动清理
+func (r *KubeAppReconciler) recordPvcRetained(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	if kubeapp.Spec.Pvc == nil {
+		return nil
+	}
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: custom.PvcName(kubeapp)}, &pvc); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if pvc.DeletionTimestamp == nil && pvcOwnedBy(kubeapp, &pvc) {
+		observePvcProtectionSkip(kubeapp, string(appsv1alpha1.PvcReclaimRetain))
+		r.event(kubeapp, corev1.EventTypeNormal, "PvcRetained", "PVC %s is no longer used but kept per reclaimPolicy Retain, delete it manually", pvc.Name)
+	}
+	return nil
+}
+
+// reportTeardown 把当前回收步骤写入 Terminating / Ready 条件，并稍后重试
+func (r *KubeAppReconciler) reportTeardown(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, reason, message string) (ctrl.Result, error) {
+	orig := kubeapp.DeepCopy()
+	meta.SetStatusCondition(&kubeapp.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionTerminating,
+		Status:             metav1.ConditionTrue,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: kubeapp.Generation,
+	})
+	meta.SetStatusCondition(&kubeapp.Status.Conditions, metav1.Condition{
+		Type:               appsv1alpha1.ConditionReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Terminating",
+		Message:            message,
+		ObservedGeneration: kubeapp.Generation,
+	})
+	observeStatusMetrics(kubeapp)
+	if err := r.Status().Patch(ctx, kubeapp, client.MergeFrom(orig)); err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: teardownRequeueInterval}, nil
+}
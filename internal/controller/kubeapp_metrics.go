@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// 一次调和的结果，写入 kubeapp_reconcile_total 的 result 标签
+const (
+	reconcileSucceeded = "success"
+	reconcileFailed    = "error"
+	reconcileInvalid   = "invalid"
+	reconcilePaused    = "paused"
+	reconcileSkipped   = "skipped"
+)
+
+// 子资源操作的结果，写入 kubeapp_child_reconcile_total 的 result 标签
+const (
+	childSucceeded = "success"
+	childFailed    = "error"
+	childConflict  = "conflict"
+)
+
+// 与 controller-runtime 自带的指标一起通过 manager 的 metrics 端点暴露，
+// config/prometheus/monitor.yaml 中的 ServiceMonitor 会采集这些指标
+var (
+	reconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubeapp_reconcile_total",
+		Help: "Number of KubeApp reconciles by result (success, error, invalid, paused, skipped).",
+	}, []string{"namespace", "name", "result"})
+
+	childReconcileTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubeapp_child_reconcile_total",
+		Help: "Number of apply and delete operations on KubeApp child resources by kind and result (success, error, conflict).",
+	}, []string{"namespace", "name", "kind", "operation", "result"})
+
+	statusCondition = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kubeapp_status_condition",
+		Help: "Condition of a KubeApp; 1 for the current status of each condition type and 0 for the others.",
+	}, []string{"namespace", "name", "type", "status"})
+
+	replicasDesired = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kubeapp_replicas_desired",
+		Help: "Desired replicas of the KubeApp workload (Deployment, StatefulSet or DaemonSet).",
+	}, []string{"namespace", "name"})
+
+	replicasReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kubeapp_replicas_ready",
+		Help: "Ready replicas of the KubeApp workload (Deployment, StatefulSet or DaemonSet).",
+	}, []string{"namespace", "name"})
+
+	pvcProtectionSkips = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kubeapp_pvc_protection_skips_total",
+		Help: "Number of times a PVC was not deleted to protect its data, by reason (Retain, SnapshotUnavailable, SnapshotMismatch, Unlabelled).",
+	}, []string{"namespace", "name", "reason"})
+)
+
+// metricConditionTypes 是导出为 kubeapp_status_condition 的条件类型，不存在的条件会删除对应的时间序列
+var metricConditionTypes = []string{
+	appsv1alpha1.ConditionReady,
+	appsv1alpha1.ConditionProgressing,
+	appsv1alpha1.ConditionDegraded,
+	appsv1alpha1.ConditionReconcileError,
+	appsv1alpha1.ConditionTerminating,
+	appsv1alpha1.ConditionFieldConflict,
+	appsv1alpha1.ConditionDisruptionBudget,
+	appsv1alpha1.ConditionPodSecurity,
+	appsv1alpha1.ConditionPaused,
+}
+
+// metricConditionStatuses 与 kube-state-metrics 一致，status 标签取小写
+var metricConditionStatuses = []metav1.ConditionStatus{metav1.ConditionTrue, metav1.ConditionFalse, metav1.ConditionUnknown}
+
+func init() {
+	metrics.Registry.MustRegister(reconcileTotal, childReconcileTotal, statusCondition, replicasDesired, replicasReady, pvcProtectionSkips)
+}
+
+// observeReconcile 记录一次调和的结果
+func observeReconcile(kubeapp *appsv1alpha1.KubeApp, result string) {
+	reconcileTotal.WithLabelValues(kubeapp.Namespace, kubeapp.Name, result).Inc()
+}
+
+// observeChildReconcile 记录一次子资源 apply / delete 的结果
+func observeChildReconcile(kubeapp *appsv1alpha1.KubeApp, kind, operation, result string) {
+	childReconcileTotal.WithLabelValues(kubeapp.Namespace, kubeapp.Name, kind, operation, result).Inc()
+}
+
+// observePvcProtectionSkip 记录一次为保护数据而没有删除 PVC
+func observePvcProtectionSkip(kubeapp *appsv1alpha1.KubeApp, reason string) {
+	pvcProtectionSkips.WithLabelValues(kubeapp.Namespace, kubeapp.Name, reason).Inc()
+}
+
+// observeStatusMetrics 按计算出的 status 更新条件和副本数指标。
+// 只删除已经不存在的时间序列，避免采集时短暂缺失导致 for 10m 之类的告警重新计时
+func observeStatusMetrics(kubeapp *appsv1alpha1.KubeApp) {
+	ns, name := kubeapp.Namespace, kubeapp.Name
+	for _, condType := range metricConditionTypes {
+		cond := meta.FindStatusCondition(kubeapp.Status.Conditions, condType)
+		for _, status := range metricConditionStatuses {
+			label := strings.ToLower(string(status))
+			if cond == nil {
+				statusCondition.DeleteLabelValues(ns, name, condType, label)
+				continue
+			}
+			value := 0.0
+			if cond.Status == status {
+				value = 1
+			}
+			statusCondition.WithLabelValues(ns, name, condType, label).Set(value)
+		}
+	}
+
+	if dep := kubeapp.Status.Deployment; dep != nil {
+		replicasDesired.WithLabelValues(ns, name).Set(float64(dep.Replicas))
+		replicasReady.WithLabelValues(ns, name).Set(float64(dep.ReadyReplicas))
+	} else {
+		replicasDesired.DeleteLabelValues(ns, name)
+		replicasReady.DeleteLabelValues(ns, name)
+	}
+}
+
+// forgetKubeAppMetrics 删除已删除的 KubeApp 的全部时间序列
+func forgetKubeAppMetrics(namespace, name string) {
+	labels := prometheus.Labels{"namespace": namespace, "name": name}
+	reconcileTotal.DeletePartialMatch(labels)
+	childReconcileTotal.DeletePartialMatch(labels)
+	statusCondition.DeletePartialMatch(labels)
+	replicasDesired.DeletePartialMatch(labels)
+	replicasReady.DeletePartialMatch(labels)
+	pvcProtectionSkips.DeletePartialMatch(labels)
+}
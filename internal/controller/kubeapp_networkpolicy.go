@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// reconcileNetworkPolicy 按 spec.networkPolicy 下发或删除 NetworkPolicy。
+// 引用的其他 KubeApp 按其 deployment.name（即 Pod 的 app 标签）选择，需要先读取这些 KubeApp
+func (r *KubeAppReconciler) reconcileNetworkPolicy(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	if !custom.NetworkPolicyConfigured(kubeapp) {
+		return custom.DeleteNetworkPolicy(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+
+	appLabels := map[types.NamespacedName]string{}
+	for _, key := range custom.NetworkPolicyKubeAppRefs(kubeapp) {
+		var other appsv1alpha1.KubeApp
+		if err := r.Get(ctx, key, &other); err != nil {
+			if errors.IsNotFound(err) {
+				log_controller.Info("NetworkPolicy 引用的 KubeApp 不存在，按名称作为 app 标签", "KubeApp名称", kubeapp.Name, "引用", key.String())
+				continue
+			}
+			return err
+		}
+		if other.Spec.Deployment != nil && other.Spec.Deployment.Name != "" {
+			appLabels[key] = other.Spec.Deployment.Name
+		}
+	}
+
+	np, err := custom.NewNetworkPolicy(kubeapp, namespace, appLabels)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, np, r.Scheme); err != nil {
+		return err
+	}
+	return r.apply(ctx, kubeapp, np)
+}
+
+// networkPolicyDependents 把 KubeApp 的变化映射到在 networkPolicy 中引用它的 KubeApp，
+// 被引用方修改 deployment.name 后重新计算引用方的 Pod 选择器
+func (r *KubeAppReconciler) networkPolicyDependents(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list appsv1alpha1.KubeAppList
+	if err := r.List(ctx, &list); err != nil {
+		log_controller.Error(err, "列出 KubeApp 失败，无法更新依赖它的 NetworkPolicy", "KubeApp名称", obj.GetName())
+		return nil
+	}
+	target := types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+
+	var requests []reconcile.Request
+	for i := range list.Items {
+		for _, ref := range custom.NetworkPolicyKubeAppRefs(&list.Items[i]) {
+			if ref == target {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+				break
+			}
+		}
+	}
+	return requests
+}
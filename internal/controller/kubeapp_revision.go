@@ -0,0 +1,136 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// recordRevision 在子资源下发成功后把当前 spec 记录为 ControllerRevision。
+// spec 与已有版本相同（例如回滚）时把该版本的编号改为最新，不重复创建；超出 RevisionHistoryLimit 的旧版本被删除
+func (r *KubeAppReconciler) recordRevision(ctx context.Context, kubeapp *appsv1alpha1.KubeApp) error {
+	revisions, err := custom.ListRevisions(ctx, r.Client, kubeapp)
+	if err != nil {
+		return err
+	}
+	current, err := custom.FindRevision(revisions, kubeapp)
+	if err != nil {
+		return err
+	}
+
+	var latest int64
+	for i := range revisions {
+		if revisions[i].Revision > latest {
+			latest = revisions[i].Revision
+		}
+	}
+
+	switch {
+	case current >= 0 && revisions[current].Revision == latest:
+		return nil
+	case current >= 0:
+		rev := &revisions[current]
+		patch := client.MergeFrom(rev.DeepCopy())
+		previous := rev.Revision
+		rev.Revision = latest + 1
+		if err := r.Patch(ctx, rev, patch); err != nil {
+			return err
+		}
+		r.event(kubeapp, corev1.EventTypeNormal, "RevisionRestored", "Spec of revision %d is now revision %d", previous, rev.Revision)
+		log_controller.Info("KubeApp spec 与历史版本一致，更新版本编号", "KubeApp名称", kubeapp.Name, "原版本", previous, "新版本", rev.Revision)
+		// 编号已变化，重新排序后再清理
+		revisions, err = custom.ListRevisions(ctx, r.Client, kubeapp)
+		if err != nil {
+			return err
+		}
+	default:
+		rev, err := r.createRevision(ctx, kubeapp, latest+1)
+		if err != nil || rev == nil {
+			return err
+		}
+		r.event(kubeapp, corev1.EventTypeNormal, "RevisionCreated", "Recorded spec as revision %d", rev.Revision)
+		log_controller.Info("记录 KubeApp 版本", "KubeApp名称", kubeapp.Name, "版本", rev.Revision, "名称", rev.Name)
+		revisions = append(revisions, *rev)
+	}
+
+	for i := 0; i < len(revisions)-custom.RevisionHistoryLimit; i++ {
+		if err := client.IgnoreNotFound(r.Delete(ctx, &revisions[i])); err != nil {
+			return err
+		}
+		log_controller.Info("清理 KubeApp 旧版本", "KubeApp名称", kubeapp.Name, "版本", revisions[i].Revision)
+	}
+	return nil
+}
+
+// createRevision 创建编号为 revision 的 ControllerRevision。名称已被占用时读取该版本：
+// 记录的 spec 相同说明缓存尚未看到刚创建的版本，返回 nil；不同则是哈希冲突，递增冲突计数换一个名称重试
+func (r *KubeAppReconciler) createRevision(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, revision int64) (*appsv1.ControllerRevision, error) {
+	for collisionCount := int32(0); ; collisionCount++ {
+		rev, err := custom.NewRevision(kubeapp, revision, collisionCount)
+		if err != nil {
+			return nil, err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, rev, r.Scheme); err != nil {
+			return nil, err
+		}
+		err = r.Create(ctx, rev)
+		if err == nil {
+			return rev, nil
+		}
+		if !errors.IsAlreadyExists(err) {
+			return nil, err
+		}
+
+		existing := &appsv1.ControllerRevision{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(rev), existing); err != nil {
+			return nil, err
+		}
+		matched, err := custom.RevisionMatches(existing, kubeapp)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			return nil, nil
+		}
+		log_controller.Info("ControllerRevision 名称哈希冲突，追加冲突计数", "KubeApp名称", kubeapp.Name, "名称", rev.Name, "冲突计数", collisionCount+1)
+	}
+}
+
+// observeRevision 找到记录当前 spec 的 ControllerRevision，写入 status.currentRevision；
+// 暂停协调等原因导致当前 spec 尚未记录时保持原值
+func (r *KubeAppReconciler) observeRevision(ctx context.Context, kubeapp *appsv1alpha1.KubeApp) error {
+	revisions, err := custom.ListRevisions(ctx, r.Client, kubeapp)
+	if err != nil {
+		return err
+	}
+	current, err := custom.FindRevision(revisions, kubeapp)
+	if err != nil {
+		return err
+	}
+	if current >= 0 {
+		kubeapp.Status.CurrentRevision = revisions[current].Revision
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// reconcileGatewayRoute 按 spec.routing.gateway 下发或删除 HTTPRoute，可以与 Ingress 同时存在，便于迁移
+func (r *KubeAppReconciler) reconcileGatewayRoute(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	if !custom.GatewayRouteConfigured(kubeapp) {
+		return custom.DeleteHTTPRoute(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+
+	route, err := custom.NewHTTPRoute(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, route, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.apply(ctx, kubeapp, route); err != nil {
+		if meta.IsNoMatchError(err) {
+			r.event(kubeapp, corev1.EventTypeWarning, "GatewayAPIMissing", "Gateway API CRDs are not installed, HTTPRoute %s was not created", route.GetName())
+			return fmt.Errorf("Gateway API 未安装，无法创建 HTTPRoute %s: %w", route.GetName(), err)
+		}
+		return err
+	}
+	return nil
+}
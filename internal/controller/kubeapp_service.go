@@ -0,0 +1,67 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileExtraServices 下发 spec.extraServices，并删除已从 spec 中移除的附加 Service。
+// enableService 为 false 时全部删除
+func (r *KubeAppReconciler) reconcileExtraServices(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	desired := map[string]bool{}
+	if kubeapp.Spec.EnableService && len(kubeapp.Spec.ExtraServices) > 0 {
+		services, err := custom.NewExtraServices(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		for _, svc := range services {
+			if err := ctrl.SetControllerReference(kubeapp, svc, r.Scheme); err != nil {
+				return err
+			}
+			if err := r.apply(ctx, kubeapp, svc); err != nil {
+				return err
+			}
+			desired[svc.Name] = true
+		}
+	}
+
+	var svcList corev1.ServiceList
+	if err := r.List(ctx, &svcList, client.InNamespace(namespace), client.HasLabels{custom.ExtraServiceLabel}); err != nil {
+		return err
+	}
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if desired[svc.Name] || !metav1.IsControlledBy(svc, kubeapp) || svc.DeletionTimestamp != nil {
+			continue
+		}
+		if err := r.Delete(ctx, svc); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		log_controller.Info("附加 Service 已从 spec 中移除，删除", "Service名称", svc.Name)
+		r.recordChildDeleted(kubeapp, "Service", svc.Name)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// reconcileServiceAccount 按 spec.serviceAccount 下发 ServiceAccount 以及可选的 Role / RoleBinding，
+// 在工作负载之前执行，Pod 创建时 ServiceAccount 已经存在
+func (r *KubeAppReconciler) reconcileServiceAccount(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	if !custom.ServiceAccountConfigured(kubeapp) {
+		if err := custom.DeleteServiceAccountRole(ctx, r.childClient(kubeapp), kubeapp, namespace); err != nil {
+			return err
+		}
+		return custom.DeleteServiceAccount(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+
+	sa, err := custom.NewServiceAccount(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, sa, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.apply(ctx, kubeapp, sa); err != nil {
+		return err
+	}
+
+	if !custom.ServiceAccountRoleConfigured(kubeapp) {
+		return custom.DeleteServiceAccountRole(ctx, r.childClient(kubeapp), kubeapp, namespace)
+	}
+	role, binding, err := custom.NewServiceAccountRole(kubeapp, namespace)
+	if err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, role, r.Scheme); err != nil {
+		return err
+	}
+	if err := r.apply(ctx, kubeapp, role); err != nil {
+		return err
+	}
+	if err := ctrl.SetControllerReference(kubeapp, binding, r.Scheme); err != nil {
+		return err
+	}
+	return r.apply(ctx, kubeapp, binding)
+}
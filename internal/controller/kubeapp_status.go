@@ -0,0 +1,751 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// progressingRequeueInterval 子资源未就绪时重新检查状态的间隔
+const progressingRequeueInterval = 15 * time.Second
+
+// childObservation 汇总子资源观测结果，用于计算 Conditions
+type childObservation struct {
+	progressing []string
+	degraded    []string
+}
+
+// updateStatus 汇总子资源状态，计算 Conditions 并通过 status 子资源写回 KubeApp
+func (r *KubeAppReconciler) updateStatus(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, reconcileErr error) (ctrl.Result, error) {
+	orig := kubeapp.DeepCopy()
+	status := &kubeapp.Status
+	obs := &childObservation{}
+
+	var err error
+	if status.Deployment, err = r.observeWorkload(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Job, err = r.observeBatchWorkload(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Autoscaling, err = r.observeAutoscaling(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = r.observeDisruptionBudget(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = r.observePodSecurity(ctx, kubeapp); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err = r.observeRevision(ctx, kubeapp); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Service, err = r.observeService(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Ingress, err = r.observeIngress(ctx, kubeapp); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.HTTPRoute, err = r.observeHTTPRoute(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+	if status.Pvc, err = r.observePvc(ctx, kubeapp, obs); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	setConditions(kubeapp, obs, reconcileErr, overriddenConflicts(ctx))
+	setPausedCondition(kubeapp)
+	// 暂停协调期间 spec 没有被下发，observedGeneration 保持不变
+	if !custom.ReconcilePaused(kubeapp) {
+		status.ObservedGeneration = kubeapp.Generation
+	}
+	observeStatusMetrics(kubeapp)
+
+	if !equality.Semantic.DeepEqual(orig.Status, kubeapp.Status) {
+		if err := r.Status().Patch(ctx, kubeapp, client.MergeFrom(orig)); err != nil {
+			if errors.IsNotFound(err) {
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+	}
+
+	if len(obs.progressing) > 0 {
+		return ctrl.Result{RequeueAfter: progressingRequeueInterval}, nil
+	}
+	return ctrl.Result{}, nil
+}
+
+// setConditions 根据观测结果和 reconcile 错误设置标准 Condition 以及 FieldConflict，
+// overridden 是本次调和中检测到并已强制接管的字段冲突
+func setConditions(kubeapp *appsv1alpha1.KubeApp, obs *childObservation, reconcileErr error, overridden []*applyConflictError) {
+	gen := kubeapp.Generation
+	conds := &kubeapp.Status.Conditions
+
+	if reconcileErr != nil {
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionReconcileError,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ReconcileFailed",
+			Message:            reconcileErr.Error(),
+			ObservedGeneration: gen,
+		})
+	} else {
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionReconcileError,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ReconcileSucceeded",
+			Message:            "All enabled child resources were applied",
+			ObservedGeneration: gen,
+		})
+	}
+
+	var conflict *applyConflictError
+	if stderrors.As(reconcileErr, &conflict) {
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionFieldConflict,
+			Status:             metav1.ConditionTrue,
+			Reason:             "FieldManagerConflict",
+			Message:            conflict.message(),
+			ObservedGeneration: gen,
+		})
+	} else if len(overridden) > 0 {
+		var messages []string
+		for _, c := range overridden {
+			messages = append(messages, c.message())
+		}
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionFieldConflict,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ConflictOverridden",
+			Message:            "Took over fields owned by another manager: " + strings.Join(messages, "; "),
+			ObservedGeneration: gen,
+		})
+	} else {
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionFieldConflict,
+			Status:             metav1.ConditionFalse,
+			Reason:             "NoConflict",
+			Message:            "No field conflicts with other managers",
+			ObservedGeneration: gen,
+		})
+	}
+
+	if len(obs.progressing) > 0 {
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionProgressing,
+			Status:             metav1.ConditionTrue,
+			Reason:             "RollingOut",
+			Message:            strings.Join(obs.progressing, "; "),
+			ObservedGeneration: gen,
+		})
+	} else {
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionProgressing,
+			Status:             metav1.ConditionFalse,
+			Reason:             "RolloutComplete",
+			Message:            "All enabled child resources are up to date",
+			ObservedGeneration: gen,
+		})
+	}
+
+	switch {
+	case reconcileErr != nil:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ReconcileFailed",
+			Message:            reconcileErr.Error(),
+			ObservedGeneration: gen,
+		})
+	case len(obs.degraded) > 0:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionTrue,
+			Reason:             "ChildResourceFailed",
+			Message:            strings.Join(obs.degraded, "; "),
+			ObservedGeneration: gen,
+		})
+	default:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionDegraded,
+			Status:             metav1.ConditionFalse,
+			Reason:             "AsExpected",
+			Message:            "No child resource reports a failure",
+			ObservedGeneration: gen,
+		})
+	}
+
+	switch {
+	case reconcileErr != nil:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "ReconcileFailed",
+			Message:            reconcileErr.Error(),
+			ObservedGeneration: gen,
+		})
+	case len(obs.degraded) > 0:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Degraded",
+			Message:            strings.Join(obs.degraded, "; "),
+			ObservedGeneration: gen,
+		})
+	case len(obs.progressing) > 0:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionReady,
+			Status:             metav1.ConditionFalse,
+			Reason:             "Progressing",
+			Message:            strings.Join(obs.progressing, "; "),
+			ObservedGeneration: gen,
+		})
+	default:
+		meta.SetStatusCondition(conds, metav1.Condition{
+			Type:               appsv1alpha1.ConditionReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             "Available",
+			Message:            "All enabled child resources are ready",
+			ObservedGeneration: gen,
+		})
+	}
+}
+
+// observeWorkload 按 workloadType 汇总工作负载状态，结果统一写入 status.deployment
+func (r *KubeAppReconciler) observeWorkload(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.DeploymentStatusSummary, error) {
+	if !kubeapp.Spec.EnableDeployment || kubeapp.Spec.Deployment == nil {
+		return nil, nil
+	}
+	switch kubeapp.Spec.EffectiveWorkloadType() {
+	case appsv1alpha1.WorkloadStatefulSet:
+		return r.observeStatefulSet(ctx, kubeapp, obs)
+	case appsv1alpha1.WorkloadDaemonSet:
+		return r.observeDaemonSet(ctx, kubeapp, obs)
+	case appsv1alpha1.WorkloadDeployment:
+		return r.observeDeployment(ctx, kubeapp, obs)
+	}
+	return nil, nil
+}
+
+// observeBatchWorkload 汇总 Job / CronJob 的最近一次运行和最近一次成功运行，写入 status.job
+func (r *KubeAppReconciler) observeBatchWorkload(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.JobStatusSummary, error) {
+	if !kubeapp.Spec.EnableDeployment || kubeapp.Spec.Deployment == nil {
+		return nil, nil
+	}
+	switch kubeapp.Spec.EffectiveWorkloadType() {
+	case appsv1alpha1.WorkloadJob:
+		return r.observeJob(ctx, kubeapp, obs)
+	case appsv1alpha1.WorkloadCronJob:
+		return r.observeCronJob(ctx, kubeapp, obs)
+	}
+	return nil, nil
+}
+
+// observeJob 读取 Job 的运行结果：运行中视为进行中，失败视为故障。
+// 运行结束时记录 Job 的 spec 哈希，Job 被 TTL 清理后保留上一次的运行结果
+func (r *KubeAppReconciler) observeJob(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.JobStatusSummary, error) {
+	name := custom.DeploymentName(kubeapp)
+	var job batchv1.Job
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &job); err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, err
+		}
+		if last := kubeapp.Status.Job; last != nil && last.Kind == "Job" && last.SpecHash != "" {
+			if hash, err := custom.JobSpecHash(kubeapp); err == nil && hash == last.SpecHash {
+				summary := last.DeepCopy()
+				summary.Active = 0
+				if summary.LastRunResult == "Failed" {
+					obs.degraded = append(obs.degraded, fmt.Sprintf("Job %s failed", name))
+				}
+				return summary, nil
+			}
+		}
+		obs.progressing = append(obs.progressing, fmt.Sprintf("Job %s has not been created yet", name))
+		return nil, nil
+	}
+
+	summary := &appsv1alpha1.JobStatusSummary{
+		Name:        job.Name,
+		Kind:        "Job",
+		Active:      job.Status.Active,
+		LastRunTime: job.Status.StartTime,
+	}
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			summary.LastRunResult = "Succeeded"
+			summary.LastSuccessfulTime = job.Status.CompletionTime
+			summary.SpecHash = job.Annotations[appsv1alpha1.SpecHashAnnotation]
+			return summary, nil
+		case batchv1.JobFailed:
+			summary.LastRunResult = "Failed"
+			summary.SpecHash = job.Annotations[appsv1alpha1.SpecHashAnnotation]
+			obs.degraded = append(obs.degraded, fmt.Sprintf("Job %s failed: %s", name, c.Message))
+			return summary, nil
+		}
+	}
+
+	summary.LastRunResult = "Running"
+	obs.progressing = append(obs.progressing, fmt.Sprintf("Job %s is running: %d active, %d succeeded, %d failed",
+		name, job.Status.Active, job.Status.Succeeded, job.Status.Failed))
+	return summary, nil
+}
+
+// observeCronJob 读取 CronJob 的调度记录：最近一次调度之后没有成功且没有运行中的 Job 视为失败
+func (r *KubeAppReconciler) observeCronJob(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.JobStatusSummary, error) {
+	name := custom.DeploymentName(kubeapp)
+	var cronJob batchv1.CronJob
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &cronJob); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("CronJob %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary := &appsv1alpha1.JobStatusSummary{
+		Name:               cronJob.Name,
+		Kind:               "CronJob",
+		Active:             int32(len(cronJob.Status.Active)),
+		LastRunTime:        cronJob.Status.LastScheduleTime,
+		LastSuccessfulTime: cronJob.Status.LastSuccessfulTime,
+	}
+	lastRun, lastSuccess := cronJob.Status.LastScheduleTime, cronJob.Status.LastSuccessfulTime
+	switch {
+	case summary.Active > 0:
+		summary.LastRunResult = "Running"
+	case lastRun == nil:
+	case lastSuccess != nil && !lastSuccess.Before(lastRun):
+		summary.LastRunResult = "Succeeded"
+	default:
+		summary.LastRunResult = "Failed"
+		obs.degraded = append(obs.degraded, fmt.Sprintf("CronJob %s: the run scheduled at %s did not succeed",
+			name, lastRun.UTC().Format(time.RFC3339)))
+	}
+	return summary, nil
+}
+
+// observeDeployment 读取 Deployment 副本状态并判断是否仍在滚动或已失败
+func (r *KubeAppReconciler) observeDeployment(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.DeploymentStatusSummary, error) {
+	name := custom.DeploymentName(kubeapp)
+	var dep appsv1.Deployment
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &dep); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("Deployment %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	desired := int32(1)
+	if dep.Spec.Replicas != nil {
+		desired = *dep.Spec.Replicas
+	}
+	summary := &appsv1alpha1.DeploymentStatusSummary{
+		Name:              dep.Name,
+		Kind:              "Deployment",
+		Replicas:          desired,
+		ReadyReplicas:     dep.Status.ReadyReplicas,
+		UpdatedReplicas:   dep.Status.UpdatedReplicas,
+		AvailableReplicas: dep.Status.AvailableReplicas,
+	}
+
+	for _, c := range dep.Status.Conditions {
+		if c.Type == appsv1.DeploymentProgressing && c.Status == corev1.ConditionFalse && c.Reason == "ProgressDeadlineExceeded" {
+			obs.degraded = append(obs.degraded, fmt.Sprintf("Deployment %s exceeded its progress deadline", name))
+			return summary, nil
+		}
+		if c.Type == appsv1.DeploymentReplicaFailure && c.Status == corev1.ConditionTrue {
+			obs.degraded = append(obs.degraded, fmt.Sprintf("Deployment %s: %s", name, c.Message))
+			return summary, nil
+		}
+	}
+
+	if dep.Status.ObservedGeneration < dep.Generation ||
+		dep.Status.UpdatedReplicas < desired ||
+		dep.Status.ReadyReplicas < desired ||
+		dep.Status.AvailableReplicas < desired {
+		obs.progressing = append(obs.progressing, fmt.Sprintf("Deployment %s: %d/%d replicas ready, %d updated",
+			name, dep.Status.ReadyReplicas, desired, dep.Status.UpdatedReplicas))
+	}
+	return summary, nil
+}
+
+// observeStatefulSet 读取 StatefulSet 副本状态，更新未完成（updateRevision 未全部就绪）视为进行中
+func (r *KubeAppReconciler) observeStatefulSet(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.DeploymentStatusSummary, error) {
+	name := custom.DeploymentName(kubeapp)
+	var sts appsv1.StatefulSet
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &sts); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("StatefulSet %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	desired := int32(1)
+	if sts.Spec.Replicas != nil {
+		desired = *sts.Spec.Replicas
+	}
+	summary := &appsv1alpha1.DeploymentStatusSummary{
+		Name:              sts.Name,
+		Kind:              "StatefulSet",
+		Replicas:          desired,
+		ReadyReplicas:     sts.Status.ReadyReplicas,
+		UpdatedReplicas:   sts.Status.UpdatedReplicas,
+		AvailableReplicas: sts.Status.AvailableReplicas,
+	}
+
+	if sts.Status.ObservedGeneration < sts.Generation ||
+		sts.Status.UpdateRevision != sts.Status.CurrentRevision ||
+		sts.Status.ReadyReplicas < desired ||
+		sts.Status.AvailableReplicas < desired {
+		obs.progressing = append(obs.progressing, fmt.Sprintf("StatefulSet %s: %d/%d replicas ready, %d updated",
+			name, sts.Status.ReadyReplicas, desired, sts.Status.UpdatedReplicas))
+	}
+	return summary, nil
+}
+
+// observeDaemonSet 读取 DaemonSet 的调度情况，副本数以应调度的节点数为准
+func (r *KubeAppReconciler) observeDaemonSet(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.DeploymentStatusSummary, error) {
+	name := custom.DeploymentName(kubeapp)
+	var ds appsv1.DaemonSet
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &ds); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("DaemonSet %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	desired := ds.Status.DesiredNumberScheduled
+	summary := &appsv1alpha1.DeploymentStatusSummary{
+		Name:              ds.Name,
+		Kind:              "DaemonSet",
+		Replicas:          desired,
+		ReadyReplicas:     ds.Status.NumberReady,
+		UpdatedReplicas:   ds.Status.UpdatedNumberScheduled,
+		AvailableReplicas: ds.Status.NumberAvailable,
+	}
+
+	if ds.Status.ObservedGeneration < ds.Generation ||
+		ds.Status.UpdatedNumberScheduled < desired ||
+		ds.Status.NumberReady < desired ||
+		ds.Status.NumberAvailable < desired {
+		obs.progressing = append(obs.progressing, fmt.Sprintf("DaemonSet %s: %d/%d pods ready, %d updated",
+			name, ds.Status.NumberReady, desired, ds.Status.UpdatedNumberScheduled))
+	}
+	if ds.Status.NumberMisscheduled > 0 {
+		obs.degraded = append(obs.degraded, fmt.Sprintf("DaemonSet %s: %d pods are running on nodes they should not run on",
+			name, ds.Status.NumberMisscheduled))
+	}
+	return summary, nil
+}
+
+// observeAutoscaling 读取 HPA 的副本范围和当前 / 期望副本数，无法扩缩容（如取不到指标）视为故障
+func (r *KubeAppReconciler) observeAutoscaling(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.AutoscalingStatusSummary, error) {
+	if !custom.AutoscalingEnabled(kubeapp) {
+		return nil, nil
+	}
+
+	name := custom.DeploymentName(kubeapp)
+	var hpa autoscalingv2.HorizontalPodAutoscaler
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &hpa); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("HorizontalPodAutoscaler %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary := &appsv1alpha1.AutoscalingStatusSummary{
+		Name:            hpa.Name,
+		MaxReplicas:     hpa.Spec.MaxReplicas,
+		CurrentReplicas: hpa.Status.CurrentReplicas,
+		DesiredReplicas: hpa.Status.DesiredReplicas,
+	}
+	if hpa.Spec.MinReplicas != nil {
+		summary.MinReplicas = *hpa.Spec.MinReplicas
+	}
+	for _, c := range hpa.Status.Conditions {
+		if (c.Type == autoscalingv2.AbleToScale || c.Type == autoscalingv2.ScalingActive) && c.Status == corev1.ConditionFalse {
+			obs.degraded = append(obs.degraded, fmt.Sprintf("HorizontalPodAutoscaler %s: %s: %s", name, c.Reason, c.Message))
+		}
+	}
+	return summary, nil
+}
+
+// observeDisruptionBudget 把 PDB 的情况写入 DisruptionBudget 条件：单副本时为 False 并提示原因，
+// 未配置 disruptionBudget 时移除该条件
+func (r *KubeAppReconciler) observeDisruptionBudget(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) error {
+	conds := &kubeapp.Status.Conditions
+	if !custom.DisruptionBudgetConfigured(kubeapp) {
+		meta.RemoveStatusCondition(conds, appsv1alpha1.ConditionDisruptionBudget)
+		return nil
+	}
+
+	cond := metav1.Condition{Type: appsv1alpha1.ConditionDisruptionBudget, ObservedGeneration: kubeapp.Generation}
+	name := custom.DeploymentName(kubeapp)
+	if replicas := custom.DisruptionBudgetReplicas(kubeapp); replicas <= 1 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "SingleReplica"
+		cond.Message = fmt.Sprintf("PodDisruptionBudget %s was not created: with %d replica it would block node drains forever; "+
+			"raise the replicas (or autoscaling.minReplicas) to at least 2", name, replicas)
+		meta.SetStatusCondition(conds, cond)
+		return nil
+	}
+
+	var pdb policyv1.PodDisruptionBudget
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &pdb); err != nil {
+		if !errors.IsNotFound(err) {
+			return err
+		}
+		obs.progressing = append(obs.progressing, fmt.Sprintf("PodDisruptionBudget %s has not been created yet", name))
+		cond.Status = metav1.ConditionUnknown
+		cond.Reason = "Pending"
+		cond.Message = fmt.Sprintf("PodDisruptionBudget %s has not been created yet", name)
+		meta.SetStatusCondition(conds, cond)
+		return nil
+	}
+
+	cond.Status = metav1.ConditionTrue
+	cond.Reason = "Created"
+	cond.Message = fmt.Sprintf("PodDisruptionBudget %s allows %d disruptions (%d/%d pods healthy)",
+		name, pdb.Status.DisruptionsAllowed, pdb.Status.CurrentHealthy, pdb.Status.ExpectedPods)
+	meta.SetStatusCondition(conds, cond)
+	return nil
+}
+
+// setPausedCondition 根据 kubeapp.io/reconcile 与 kubeapp.io/rollout 注解设置 Paused Condition，都未暂停时移除
+func setPausedCondition(kubeapp *appsv1alpha1.KubeApp) {
+	conds := &kubeapp.Status.Conditions
+	cond := metav1.Condition{Type: appsv1alpha1.ConditionPaused, Status: metav1.ConditionTrue, ObservedGeneration: kubeapp.Generation}
+	switch {
+	case custom.ReconcilePaused(kubeapp):
+		cond.Reason = "ReconcilePaused"
+		cond.Message = fmt.Sprintf("reconciliation is paused by the %s annotation; children are not created, updated or deleted",
+			appsv1alpha1.ReconcileAnnotation)
+	case custom.RolloutPaused(kubeapp):
+		cond.Reason = "RolloutPaused"
+		cond.Message = fmt.Sprintf("the rollout of Deployment %s is paused by the %s annotation",
+			custom.DeploymentName(kubeapp), appsv1alpha1.RolloutAnnotation)
+	default:
+		meta.RemoveStatusCondition(conds, appsv1alpha1.ConditionPaused)
+		return
+	}
+	meta.SetStatusCondition(conds, cond)
+}
+
+// observePodSecurity 对照命名空间 pod-security.kubernetes.io/enforce 标签检查生成的 Pod，
+// 不满足时 Pod 会被 Pod Security Admission 拒绝，通过 PodSecurity Condition 提前暴露出来
+func (r *KubeAppReconciler) observePodSecurity(ctx context.Context, kubeapp *appsv1alpha1.KubeApp) error {
+	conds := &kubeapp.Status.Conditions
+	if !kubeapp.Spec.EnableDeployment || kubeapp.Spec.Deployment == nil {
+		meta.RemoveStatusCondition(conds, appsv1alpha1.ConditionPodSecurity)
+		return nil
+	}
+
+	var ns corev1.Namespace
+	if err := r.Get(ctx, client.ObjectKey{Name: kubeapp.Namespace}, &ns); err != nil {
+		if errors.IsNotFound(err) {
+			meta.RemoveStatusCondition(conds, appsv1alpha1.ConditionPodSecurity)
+			return nil
+		}
+		return err
+	}
+	level := ns.Labels[custom.PodSecurityEnforceLabel]
+	if level != custom.PodSecurityBaseline && level != custom.PodSecurityRestricted {
+		meta.RemoveStatusCondition(conds, appsv1alpha1.ConditionPodSecurity)
+		return nil
+	}
+
+	cond := metav1.Condition{Type: appsv1alpha1.ConditionPodSecurity, ObservedGeneration: kubeapp.Generation}
+	if violations := custom.PodSecurityViolations(kubeapp, level); len(violations) > 0 {
+		cond.Status = metav1.ConditionFalse
+		cond.Reason = "EnforcementMismatch"
+		cond.Message = fmt.Sprintf("namespace %s enforces the %s Pod Security level and the pods would be rejected: %s",
+			ns.Name, level, strings.Join(violations, "; "))
+	} else {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "Compliant"
+		cond.Message = fmt.Sprintf("the pods satisfy the %s Pod Security level enforced on namespace %s", level, ns.Name)
+	}
+	meta.SetStatusCondition(conds, cond)
+	return nil
+}
+
+// namespaceKubeApps 把命名空间的变化映射到其中所有的 KubeApp
+func (r *KubeAppReconciler) namespaceKubeApps(ctx context.Context, obj client.Object) []reconcile.Request {
+	var list appsv1alpha1.KubeAppList
+	if err := r.List(ctx, &list, client.InNamespace(obj.GetName())); err != nil {
+		log_controller.Error(err, "列出 KubeApp 失败，无法更新 PodSecurity 状态", "命名空间", obj.GetName())
+		return nil
+	}
+	requests := make([]reconcile.Request, 0, len(list.Items))
+	for i := range list.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&list.Items[i])})
+	}
+	return requests
+}
+
+// observeService 读取 Service 的类型和 ClusterIP
+func (r *KubeAppReconciler) observeService(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.ServiceStatusSummary, error) {
+	if !kubeapp.Spec.EnableService || kubeapp.Spec.Service == nil {
+		return nil, nil
+	}
+
+	name := custom.ServiceName(kubeapp)
+	var svc corev1.Service
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &svc); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("Service %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &appsv1alpha1.ServiceStatusSummary{
+		Name:      svc.Name,
+		Type:      svc.Spec.Type,
+		ClusterIP: svc.Spec.ClusterIP,
+	}, nil
+}
+
+// observeIngress 读取 Ingress 控制器分配的地址（没有地址不视为未就绪）
+func (r *KubeAppReconciler) observeIngress(ctx context.Context, kubeapp *appsv1alpha1.KubeApp) (*appsv1alpha1.IngressStatusSummary, error) {
+	if !kubeapp.Spec.EnableIngress || kubeapp.Spec.Ingress == nil {
+		return nil, nil
+	}
+
+	var ing networkingv1.Ingress
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: custom.IngressName(kubeapp)}, &ing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var addresses []string
+	for _, lb := range ing.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+	return &appsv1alpha1.IngressStatusSummary{
+		Name:    ing.Name,
+		Address: strings.Join(addresses, ","),
+	}, nil
+}
+
+// observeHTTPRoute 读取各 Gateway 对 HTTPRoute 的 Accepted 条件，被拒绝视为故障；
+// Gateway 尚未处理（没有 status）不视为未就绪，与 Ingress 没有地址一致
+func (r *KubeAppReconciler) observeHTTPRoute(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.HTTPRouteStatusSummary, error) {
+	if !custom.GatewayRouteConfigured(kubeapp) {
+		return nil, nil
+	}
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(custom.HTTPRouteGVK)
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: custom.HTTPRouteName(kubeapp)}, route); err != nil {
+		if errors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	summary := &appsv1alpha1.HTTPRouteStatusSummary{Name: route.GetName()}
+	parents, _, _ := unstructured.NestedSlice(route.Object, "status", "parents")
+	for _, p := range parents {
+		parent, ok := p.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		gateway, _, _ := unstructured.NestedString(parent, "parentRef", "name")
+		if ns, _, _ := unstructured.NestedString(parent, "parentRef", "namespace"); ns != "" {
+			gateway = ns + "/" + gateway
+		}
+		conditions, _, _ := unstructured.NestedSlice(parent, "conditions")
+		for _, c := range conditions {
+			cond, ok := c.(map[string]interface{})
+			if !ok || cond["type"] != "Accepted" {
+				continue
+			}
+			switch cond["status"] {
+			case string(metav1.ConditionTrue):
+				summary.AcceptedParents = append(summary.AcceptedParents, gateway)
+			case string(metav1.ConditionFalse):
+				obs.degraded = append(obs.degraded, fmt.Sprintf("HTTPRoute %s was not accepted by Gateway %s: %v", route.GetName(), gateway, cond["message"]))
+			}
+		}
+	}
+	return summary, nil
+}
+
+// observePvc 读取 PVC 的 phase，Pending 视为进行中，Lost 视为故障
+func (r *KubeAppReconciler) observePvc(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, obs *childObservation) (*appsv1alpha1.PvcStatusSummary, error) {
+	if !kubeapp.Spec.EnablePvc || kubeapp.Spec.Pvc == nil {
+		return nil, nil
+	}
+
+	name := custom.PvcName(kubeapp)
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, client.ObjectKey{Namespace: kubeapp.Namespace, Name: name}, &pvc); err != nil {
+		if errors.IsNotFound(err) {
+			obs.progressing = append(obs.progressing, fmt.Sprintf("PVC %s has not been created yet", name))
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	switch pvc.Status.Phase {
+	case corev1.ClaimLost:
+		obs.degraded = append(obs.degraded, fmt.Sprintf("PVC %s lost its volume", name))
+	case corev1.ClaimPending:
+		obs.progressing = append(obs.progressing, fmt.Sprintf("PVC %s is pending", name))
+	}
+	return &appsv1alpha1.PvcStatusSummary{
+		Name:  pvc.Name,
+		Phase: pvc.Status.Phase,
+	}, nil
+}
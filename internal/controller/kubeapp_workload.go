@@ -0,0 +1,187 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// workloadDeleters 列出每种工作负载对应的子资源删除函数，切换类型或关闭 enableDeployment 时用来清理。
+// 删除函数只删除由该 KubeApp 控制的对象，同名的手工资源或其他控制器的资源会被保留
+var workloadDeleters = []struct {
+	workloadType appsv1alpha1.WorkloadType
+	delete       func(context.Context, client.Client, *appsv1alpha1.KubeApp, string) error
+}{
+	{appsv1alpha1.WorkloadDeployment, custom.DeleteDeployment},
+	{appsv1alpha1.WorkloadStatefulSet, custom.DeleteStatefulSet},
+	{appsv1alpha1.WorkloadStatefulSet, custom.DeleteHeadlessService},
+	{appsv1alpha1.WorkloadDaemonSet, custom.DeleteDaemonSet},
+	{appsv1alpha1.WorkloadJob, custom.DeleteJob},
+	{appsv1alpha1.WorkloadCronJob, custom.DeleteCronJob},
+}
+
+// reconcileWorkload 按 workloadType 下发 Deployment、StatefulSet（连同 headless 管理 Service）、DaemonSet、Job 或 CronJob，
+// 并删除切换类型或关闭 enableDeployment 后遗留的其他工作负载
+func (r *KubeAppReconciler) reconcileWorkload(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, namespace string) error {
+	var workloadType appsv1alpha1.WorkloadType
+	if kubeapp.Spec.EnableDeployment {
+		workloadType = kubeapp.Spec.EffectiveWorkloadType()
+	}
+
+	for _, d := range workloadDeleters {
+		if d.workloadType == workloadType {
+			continue
+		}
+		if err := d.delete(ctx, r.childClient(kubeapp), kubeapp, namespace); err != nil {
+			return err
+		}
+	}
+
+	switch workloadType {
+	case appsv1alpha1.WorkloadDeployment:
+		dep, err := custom.NewDeployment(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, dep, r.Scheme); err != nil {
+			return err
+		}
+		if dep.Spec.Replicas == nil {
+			if err := r.handOverReplicas(ctx, kubeapp, dep); err != nil {
+				return err
+			}
+		}
+		return r.apply(ctx, kubeapp, dep)
+
+	case appsv1alpha1.WorkloadStatefulSet:
+		// 管理 Service 需要先于 StatefulSet 存在，Pod 才能获得稳定的 DNS 记录
+		svc, err := custom.NewHeadlessService(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, svc, r.Scheme); err != nil {
+			return err
+		}
+		if err := r.apply(ctx, kubeapp, svc); err != nil {
+			return err
+		}
+
+		sts, err := custom.NewStatefulSet(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, sts, r.Scheme); err != nil {
+			return err
+		}
+		if sts.Spec.Replicas == nil {
+			if err := r.handOverReplicas(ctx, kubeapp, sts); err != nil {
+				return err
+			}
+		}
+		return r.apply(ctx, kubeapp, sts)
+
+	case appsv1alpha1.WorkloadDaemonSet:
+		ds, err := custom.NewDaemonSet(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, ds, r.Scheme); err != nil {
+			return err
+		}
+		return r.apply(ctx, kubeapp, ds)
+
+	case appsv1alpha1.WorkloadJob:
+		job, err := custom.NewJob(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, job, r.Scheme); err != nil {
+			return err
+		}
+		if finished, err := r.finishedJobRemoved(ctx, kubeapp, job); err != nil || finished {
+			return err
+		}
+		if err := r.replaceChangedJob(ctx, kubeapp, job); err != nil {
+			return err
+		}
+		return r.apply(ctx, kubeapp, job)
+
+	case appsv1alpha1.WorkloadCronJob:
+		cronJob, err := custom.NewCronJob(kubeapp, namespace)
+		if err != nil {
+			return err
+		}
+		if err := ctrl.SetControllerReference(kubeapp, cronJob, r.Scheme); err != nil {
+			return err
+		}
+		return r.apply(ctx, kubeapp, cronJob)
+	}
+	return nil
+}
+
+// finishedJobRemoved 判断 Job 是否已经按当前 spec 运行结束并被删除（例如 ttlSecondsAfterFinished 到期）。
+// 这种情况下不再创建 Job，否则 TTL 删除触发的调和会让一次性任务反复运行；spec 哈希变化后才会重新运行
+func (r *KubeAppReconciler) finishedJobRemoved(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, desired *batchv1.Job) (bool, error) {
+	last := kubeapp.Status.Job
+	hash := desired.Annotations[appsv1alpha1.SpecHashAnnotation]
+	if last == nil || last.Kind != "Job" || last.SpecHash == "" || last.SpecHash != hash {
+		return false, nil
+	}
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), &batchv1.Job{}); !errors.IsNotFound(err) {
+		return false, err
+	}
+	log_controller.V(1).Info("Job 已按当前 spec 运行结束并被删除，不再重建", "Job名称", desired.Name, "spec哈希", hash)
+	return true, nil
+}
+
+// replaceChangedJob Job 的 Pod 模板创建后不可修改：spec 哈希变化时删除旧 Job，随后按新 spec 重新创建（即重新运行一次）
+func (r *KubeAppReconciler) replaceChangedJob(ctx context.Context, kubeapp *appsv1alpha1.KubeApp, desired *batchv1.Job) error {
+	var existing batchv1.Job
+	if err := r.Get(ctx, client.ObjectKeyFromObject(desired), &existing); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if !metav1.IsControlledBy(&existing, kubeapp) {
+		return nil
+	}
+	if existing.DeletionTimestamp != nil {
+		return fmt.Errorf("Job %s 正在删除，等待删除完成后重建", existing.Name)
+	}
+
+	hash := desired.Annotations[appsv1alpha1.SpecHashAnnotation]
+	if existing.Annotations[appsv1alpha1.SpecHashAnnotation] == hash {
+		return nil
+	}
+	log_controller.Info("Job spec 已变化，删除旧 Job 后重建", "Job名称", existing.Name, "旧哈希", existing.Annotations[appsv1alpha1.SpecHashAnnotation], "新哈希", hash)
+	if err := r.Delete(ctx, &existing, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	r.event(kubeapp, corev1.EventTypeNormal, "JobReplaced", "Replaced Job %s because its spec changed", existing.Name)
+	return nil
+}
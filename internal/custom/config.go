@@ -0,0 +1,126 @@
+package define
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_cfg = logf.Log.WithName("config-creator")
+
+// NewConfigMaps 根据 spec.configMaps 创建 ConfigMap，data 与 files 合并为同一个 data
+func NewConfigMaps(KubeApp *appsv1alpha1.KubeApp, namespace string) ([]*corev1.ConfigMap, error) {
+	if KubeApp == nil {
+		return nil, fmt.Errorf("KubeApp 对象不能为空")
+	}
+
+	var configMaps []*corev1.ConfigMap
+	for _, spec := range KubeApp.Spec.ConfigMaps {
+		data, err := mergeConfigData(spec.Name, spec.Data, spec.Files)
+		if err != nil {
+			log_cfg.Error(err, "ConfigMap 参数验证失败", "KubeApp名称", KubeApp.Name, "ConfigMap名称", spec.Name)
+			return nil, err
+		}
+		configMaps = append(configMaps, &corev1.ConfigMap{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ConfigMap"},
+			ObjectMeta: configObjectMeta(KubeApp, spec.Name, namespace),
+			Data:       data,
+		})
+		log_cfg.Info("ConfigMap 创建成功", "名称", spec.Name, "命名空间", namespace, "键数量", len(data))
+	}
+	return configMaps, nil
+}
+
+// NewSecrets 根据 spec.secrets 创建 Secret，明文内容写入 stringData，由 API server 编码保存
+func NewSecrets(KubeApp *appsv1alpha1.KubeApp, namespace string) ([]*corev1.Secret, error) {
+	if KubeApp == nil {
+		return nil, fmt.Errorf("KubeApp 对象不能为空")
+	}
+
+	var secrets []*corev1.Secret
+	for _, spec := range KubeApp.Spec.Secrets {
+		data, err := mergeConfigData(spec.Name, spec.StringData, spec.Files)
+		if err != nil {
+			log_cfg.Error(err, "Secret 参数验证失败", "KubeApp名称", KubeApp.Name, "Secret名称", spec.Name)
+			return nil, err
+		}
+		secretType := spec.Type
+		if secretType == "" {
+			secretType = corev1.SecretTypeOpaque
+		}
+		secrets = append(secrets, &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Secret"},
+			ObjectMeta: configObjectMeta(KubeApp, spec.Name, namespace),
+			Type:       secretType,
+			StringData: data,
+		})
+		// 不记录 Secret 内容
+		log_cfg.Info("Secret 创建成功", "名称", spec.Name, "命名空间", namespace, "类型", secretType, "键数量", len(data))
+	}
+	return secrets, nil
+}
+
+// configObjectMeta 返回 ConfigMap / Secret 的元数据，managed-by 标签用于清理从 spec 中移除的对象
+func configObjectMeta(KubeApp *appsv1alpha1.KubeApp, name, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        name,
+		Namespace:   namespace,
+		Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+		Annotations: childAnnotations(KubeApp),
+	}
+}
+
+// mergeConfigData 把字面量和文件合并为 key -> 内容，key 必须合法且不能重复
+func mergeConfigData(name string, literals map[string]string, files []appsv1alpha1.ConfigFile) (map[string]string, error) {
+	if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+		return nil, fmt.Errorf("名称 %q 不合法: %s", name, strings.Join(errs, ", "))
+	}
+
+	data := make(map[string]string, len(literals)+len(files))
+	for key, value := range literals {
+		if errs := validation.IsConfigMapKey(key); len(errs) > 0 {
+			return nil, fmt.Errorf("%s 的 key %q 不合法: %s", name, key, strings.Join(errs, ", "))
+		}
+		data[key] = value
+	}
+	for _, file := range files {
+		if errs := validation.IsConfigMapKey(file.Name); len(errs) > 0 {
+			return nil, fmt.Errorf("%s 的文件名 %q 不合法: %s", name, file.Name, strings.Join(errs, ", "))
+		}
+		if _, exists := data[file.Name]; exists {
+			return nil, fmt.Errorf("%s 的 key %q 重复", name, file.Name)
+		}
+		data[file.Name] = file.Content
+	}
+	return data, nil
+}
+
+// ConfigHash 计算 spec.configMaps 与 spec.secrets 内容的哈希，写入 Pod 模板注解后，
+// 配置变化会让 Pod 模板变化并按工作负载的更新策略滚动。没有托管配置时返回空串
+func ConfigHash(KubeApp *appsv1alpha1.KubeApp) string {
+	if len(KubeApp.Spec.ConfigMaps) == 0 && len(KubeApp.Spec.Secrets) == 0 {
+		return ""
+	}
+	// map 按 key 排序序列化，结果稳定
+	data, err := json.Marshal(struct {
+		ConfigMaps []appsv1alpha1.ConfigMapSpec `json:"configMaps"`
+		Secrets    []appsv1alpha1.SecretSpec    `json:"secrets"`
+	}{KubeApp.Spec.ConfigMaps, KubeApp.Spec.Secrets})
+	if err != nil {
+		log_cfg.Error(err, "配置哈希计算失败", "KubeApp名称", KubeApp.Name)
+		return ""
+	}
+	// 使用 sha256 而不是 fnv，注解可见，避免通过哈希反推出简单的 Secret 内容
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
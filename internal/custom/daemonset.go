@@ -0,0 +1,156 @@
+package define
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_ds = logf.Log.WithName("daemonset-creator")
+
+// DaemonSetInfo 是 /kube/daemonset/query 返回的一行，列与 kubectl get ds 对应
+type DaemonSetInfo struct {
+	AppName      string   `json:"app_name"`
+	Namespace    string   `json:"namespace"`
+	Desired      int32    `json:"desired"`
+	Current      int32    `json:"current"`
+	Ready        int32    `json:"ready"`
+	UpToDate     int32    `json:"up_to_date"`
+	Available    int32    `json:"available"`
+	NodeSelector string   `json:"node_selector"`
+	Image        []string `json:"image"`
+	CreatedAt    string   `json:"created_at"`
+	Age          string   `json:"age"`
+}
+
+// DeleteDaemonSet 删除 KubeApp 对应的 DaemonSet
+func DeleteDaemonSet(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	ds := &appsv1.DaemonSet{}
+	ds.SetName(DeploymentName(KubeApp))
+	ds.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, ds, KubeApp)
+}
+
+// NewDaemonSet 根据 KubeApp 创建 DaemonSet，Pod 模板与 Deployment 使用同一套构建逻辑，
+// 运行在哪些节点上由 deployment.nodeSelector / tolerations / affinity 决定，replicas 不生效
+func NewDaemonSet(KubeApp *appsv1alpha1.KubeApp, namespace string) (*appsv1.DaemonSet, error) {
+	if KubeApp == nil {
+		return nil, fmt.Errorf("KubeApp 对象不能为空")
+	}
+
+	log_ds.Info("开始创建 DaemonSet", "KubeApp名称", KubeApp.Name, "命名空间", namespace)
+
+	if KubeApp.Spec.Deployment == nil {
+		return nil, fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+	if err := validateDeploymentSpec(KubeApp.Spec.Deployment); err != nil {
+		log_ds.Error(err, "DaemonSet 容器规格验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	if err := validateDaemonSetSpec(KubeApp.Spec.DaemonSet); err != nil {
+		log_ds.Error(err, "DaemonSet 更新策略验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+
+	updateStrategy := prepareDaemonSetUpdateStrategy(KubeApp.Spec.DaemonSet)
+	daemonSet := &appsv1.DaemonSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "DaemonSet"},
+		ObjectMeta: workloadObjectMeta(KubeApp, namespace),
+		Spec: appsv1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": KubeApp.Spec.Deployment.Name,
+				},
+			},
+			Template:       preparePodTemplate(KubeApp),
+			UpdateStrategy: updateStrategy,
+		},
+	}
+
+	log_ds.Info("DaemonSet 创建成功", "名称", daemonSet.Name, "命名空间", daemonSet.Namespace,
+		"updateStrategy", updateStrategy.Type, "tolerations数量", len(KubeApp.Spec.Deployment.Tolerations))
+	return daemonSet, nil
+}
+
+// daemonSetUpdateStrategyType 返回生效的更新策略，未设置时为 RollingUpdate
+func daemonSetUpdateStrategyType(ds *appsv1alpha1.DaemonSetSpec) appsv1.DaemonSetUpdateStrategyType {
+	if ds == nil || ds.UpdateStrategy == "" {
+		return appsv1.RollingUpdateDaemonSetStrategyType
+	}
+	return ds.UpdateStrategy
+}
+
+// prepareDaemonSetUpdateStrategy 构建更新策略：RollingUpdate 时 maxUnavailable 默认为 1，OnDelete 不带滚动参数
+func prepareDaemonSetUpdateStrategy(ds *appsv1alpha1.DaemonSetSpec) appsv1.DaemonSetUpdateStrategy {
+	strategyType := daemonSetUpdateStrategyType(ds)
+	if strategyType == appsv1.OnDeleteDaemonSetStrategyType {
+		return appsv1.DaemonSetUpdateStrategy{Type: strategyType}
+	}
+
+	maxUnavailable := intstr.FromInt32(1)
+	if ds != nil && ds.MaxUnavailable != nil {
+		maxUnavailable = *ds.MaxUnavailable
+	}
+	return appsv1.DaemonSetUpdateStrategy{
+		Type:          strategyType,
+		RollingUpdate: &appsv1.RollingUpdateDaemonSet{MaxUnavailable: &maxUnavailable},
+	}
+}
+
+// validateDaemonSetSpec 校验 maxUnavailable：只能用于 RollingUpdate，取值为正整数或 1%-100% 的百分比
+func validateDaemonSetSpec(ds *appsv1alpha1.DaemonSetSpec) error {
+	if ds == nil || ds.MaxUnavailable == nil {
+		return nil
+	}
+	if daemonSetUpdateStrategyType(ds) == appsv1.OnDeleteDaemonSetStrategyType {
+		return fmt.Errorf("maxUnavailable 只能用于 RollingUpdate 更新策略")
+	}
+
+	return validateIntOrPercent("maxUnavailable", *ds.MaxUnavailable, false)
+}
+
+// ListDaemonSets 使用 controller-runtime client 查询命名空间下的 DaemonSet
+func ListDaemonSets(namespace string) ([]DaemonSetInfo, error) {
+	if GlobalClient == nil {
+		return nil, fmt.Errorf("k8s client 未初始化")
+	}
+
+	var dsList appsv1.DaemonSetList
+	if err := GlobalClient.List(context.Background(), &dsList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	var result []DaemonSetInfo
+	for _, ds := range dsList.Items {
+		var images []string
+		for _, c := range ds.Spec.Template.Spec.Containers {
+			images = append(images, c.Image)
+		}
+
+		createdAt := ds.CreationTimestamp.Time
+		result = append(result, DaemonSetInfo{
+			AppName:      ds.Name,
+			Namespace:    ds.Namespace,
+			Desired:      ds.Status.DesiredNumberScheduled,
+			Current:      ds.Status.CurrentNumberScheduled,
+			Ready:        ds.Status.NumberReady,
+			UpToDate:     ds.Status.UpdatedNumberScheduled,
+			Available:    ds.Status.NumberAvailable,
+			NodeSelector: utils.FormatLabels(ds.Spec.Template.Spec.NodeSelector),
+			Image:        images,
+			CreatedAt:    createdAt.In(loc).Format("2006-01-02 15:04:05"),
+			Age:          utils.FormatAge(createdAt),
+		})
+	}
+	return result, nil
+}
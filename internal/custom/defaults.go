@@ -0,0 +1,79 @@
+package define
+
+import (
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+)
+
+// DefaultReplicas 未设置（或设置为非正数）副本数时使用的默认值，
+// NewDeployment、模板构建和准入 webhook 都以它为准
+const DefaultReplicas int32 = 1
+
+// DefaultKubeApp 把子资源构建时隐式使用的默认值写回 spec，
+// 使存储下来的对象就是生效的配置（kubectl get -o yaml 可见）。
+// 默认值与 NewDeployment / NewService / NewIngress / NewHTTPRoute / NewNetworkPolicy 使用同一套函数，保证两边一致。
+func DefaultKubeApp(KubeApp *appsv1alpha1.KubeApp) {
+	spec := &KubeApp.Spec
+
+	spec.WorkloadType = spec.EffectiveWorkloadType()
+	if sts := spec.StatefulSet; sts != nil {
+		sts.PodManagementPolicy = podManagementPolicy(sts)
+	}
+	if ds := spec.DaemonSet; ds != nil {
+		ds.UpdateStrategy = daemonSetUpdateStrategyType(ds)
+	}
+
+	if dep := spec.Deployment; dep != nil {
+		if dep.Replicas == nil || *dep.Replicas <= 0 {
+			replicas := DefaultReplicas
+			dep.Replicas = &replicas
+		}
+		if strategy := dep.Strategy; strategy != nil {
+			strategy.Type = deploymentStrategyType(strategy)
+			if rolling := prepareDeploymentStrategy(strategy).RollingUpdate; rolling != nil {
+				strategy.MaxSurge, strategy.MaxUnavailable = rolling.MaxSurge, rolling.MaxUnavailable
+			}
+		}
+		if as := spec.Autoscaling; as != nil && as.MinReplicas == nil {
+			minReplicas := autoscalingMinReplicas(KubeApp)
+			as.MinReplicas = &minReplicas
+		}
+	}
+
+	if svc := spec.Service; svc != nil {
+		svc.Type = determineServiceType(svc)
+	}
+	for i := range spec.ExtraServices {
+		spec.ExtraServices[i].Type = determineServiceType(&spec.ExtraServices[i])
+	}
+
+	if ing := spec.Ingress; ing != nil {
+		if len(ing.Rules) == 0 {
+			ing.Path = normalizePath(ing.Path)
+			ing.PathType = *getPathType(ing.PathType)
+		}
+		for i := range ing.Rules {
+			for j := range ing.Rules[i].Paths {
+				path := &ing.Rules[i].Paths[j]
+				path.Path = normalizePath(path.Path)
+				path.PathType = *getPathType(path.PathType)
+			}
+		}
+	}
+
+	if np := spec.NetworkPolicy; np != nil && np.IngressFrom != nil && np.IngressFrom.IngressController != nil {
+		np.IngressFrom.IngressController.Namespace = ingressControllerNamespace(np.IngressFrom.IngressController)
+	}
+
+	if spec.Routing != nil && spec.Routing.Gateway != nil {
+		for i := range spec.Routing.Gateway.Rules {
+			for j := range spec.Routing.Gateway.Rules[i].Matches {
+				match := &spec.Routing.Gateway.Rules[i].Matches[j]
+				match.Path = normalizePath(match.Path)
+				match.PathType = routePathType(match.PathType)
+				for k := range match.Headers {
+					match.Headers[k].Type = headerMatchType(match.Headers[k].Type)
+				}
+			}
+		}
+	}
+}
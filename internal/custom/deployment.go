@@ -40,14 +40,10 @@ var log_dp = logf.Log.WithName("deployment-creator")
 //  Deployment 根据 KubeApp 自定义资源false delete 删除deployment  
 
 func DeleteDeployment(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
-    name := KubeApp.Spec.Deployment.Name
-    if name == "" {
-        name = KubeApp.Name
-    }
     dep := &appsv1.Deployment{}
-    dep.SetName(name)
+    dep.SetName(DeploymentName(KubeApp))
     dep.SetNamespace(namespace)
-    return utils.DeleteIfExists(ctx, cli, dep)
+    return utils.DeleteIfControlled(ctx, cli, dep, KubeApp)
 }
 
 
@@ -71,18 +67,81 @@ func NewDeployment(KubeApp *appsv1alpha1.KubeApp, namespace string) (*appsv1.Dep
         log_dp.Error(err, "Deployment 规格验证失败", "KubeApp名称", KubeApp.Name)
         return nil, err
     }
+    if err := validateDeploymentStrategy(KubeApp.Spec.Deployment.Strategy); err != nil {
+        log_dp.Error(err, "部署策略验证失败", "KubeApp名称", KubeApp.Name)
+        return nil, err
+    }
+
+    // 设置副本数（启用 autoscaling 时由 HPA 管理）
+    replicas := workloadReplicas(KubeApp)
 
-    // 设置副本数
-    replicas := int32(1)
+    // 构建 Deployment 对象
+    // server-side apply 需要显式的 apiVersion/kind
+    deployment := &appsv1.Deployment{
+        TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "Deployment"},
+        ObjectMeta: workloadObjectMeta(KubeApp, namespace),
+        Spec: appsv1.DeploymentSpec{
+            Replicas: replicas,
+            Selector: &metav1.LabelSelector{
+                MatchLabels: map[string]string{
+                    "app": KubeApp.Spec.Deployment.Name,
+                },
+            },
+            Template: preparePodTemplate(KubeApp),
+            Strategy: prepareDeploymentStrategy(KubeApp.Spec.Deployment.Strategy),
+            // kubeapp.io/rollout: paused 时暂停滚动更新，移除注解后该字段不再声明，恢复为 false
+            Paused:   RolloutPaused(KubeApp),
+        },
+    }
+    if strategy := KubeApp.Spec.Deployment.Strategy; strategy != nil {
+        deployment.Spec.MinReadySeconds = strategy.MinReadySeconds
+        deployment.Spec.ProgressDeadlineSeconds = strategy.ProgressDeadlineSeconds
+        deployment.Spec.RevisionHistoryLimit = strategy.RevisionHistoryLimit
+    }
+
+    log_dp.Info("Deployment 创建成功", "名称", deployment.Name, "命名空间", deployment.Namespace)
+    return deployment, nil
+}
+
+// prepareReplicas 计算副本数，未设置或为非正数时使用 DefaultReplicas
+func prepareReplicas(KubeApp *appsv1alpha1.KubeApp) int32 {
+    replicas := DefaultReplicas
     if KubeApp.Spec.Deployment.Replicas != nil && *KubeApp.Spec.Deployment.Replicas > 0 {
         replicas = *KubeApp.Spec.Deployment.Replicas
     }
     log_dp.V(1).Info("设置副本数", "副本数", replicas, "KubeApp名称", KubeApp.Name)
+    return replicas
+}
+
+// workloadReplicas 返回写入工作负载的副本数。启用 autoscaling 时返回 nil，
+// server-side apply 不再声明 spec.replicas，避免与 HPA 来回争抢
+func workloadReplicas(KubeApp *appsv1alpha1.KubeApp) *int32 {
+    if AutoscalingEnabled(KubeApp) {
+        log_dp.V(1).Info("已启用 autoscaling，副本数交给 HPA 管理", "KubeApp名称", KubeApp.Name)
+        return nil
+    }
+    replicas := prepareReplicas(KubeApp)
+    return &replicas
+}
+
+// workloadObjectMeta 返回 Deployment / StatefulSet 共用的元数据
+func workloadObjectMeta(KubeApp *appsv1alpha1.KubeApp, namespace string) metav1.ObjectMeta {
+    return metav1.ObjectMeta{
+        Name:        KubeApp.Spec.Deployment.Name,
+        Namespace:   namespace,
+        Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+        Annotations: childAnnotations(KubeApp),
+    }
+}
+
+// preparePodTemplate 构建 Deployment / StatefulSet 共用的 Pod 模板
+func preparePodTemplate(KubeApp *appsv1alpha1.KubeApp) corev1.PodTemplateSpec {
+    spec := KubeApp.Spec.Deployment
 
     // terminationGracePeriodSeconds
     var terminationGracePeriodSeconds *int64
-    if KubeApp.Spec.Deployment.TerminationGracePeriodSeconds != nil {
-        terminationGracePeriodSeconds = KubeApp.Spec.Deployment.TerminationGracePeriodSeconds
+    if spec.TerminationGracePeriodSeconds != nil {
+        terminationGracePeriodSeconds = spec.TerminationGracePeriodSeconds
         log_dp.Info("设置 terminationGracePeriodSeconds", "值", *terminationGracePeriodSeconds)
     } else {
         log_dp.Info("未设置 terminationGracePeriodSeconds，使用 Kubernetes 默认值")
@@ -90,8 +149,8 @@ func NewDeployment(KubeApp *appsv1alpha1.KubeApp, namespace string) (*appsv1.Dep
 
     // imagePullSecrets
     var imagePullSecrets []corev1.LocalObjectReference
-    if len(KubeApp.Spec.Deployment.ImagePullSecrets) > 0 {
-        imagePullSecrets = convertImagePullSecrets(KubeApp.Spec.Deployment.ImagePullSecrets)
+    if len(spec.ImagePullSecrets) > 0 {
+        imagePullSecrets = convertImagePullSecrets(spec.ImagePullSecrets)
         log_dp.Info("配置 imagePullSecrets", "数量", len(imagePullSecrets))
     } else {
         log_dp.Info("未配置 imagePullSecrets，使用默认拉取策略")
@@ -99,8 +158,8 @@ func NewDeployment(KubeApp *appsv1alpha1.KubeApp, namespace string) (*appsv1.Dep
 
     // dnsConfig
     var dnsConfig *corev1.PodDNSConfig
-    if KubeApp.Spec.Deployment.DNSConfig != nil {
-        dnsConfig = KubeApp.Spec.Deployment.DNSConfig
+    if spec.DNSConfig != nil {
+        dnsConfig = spec.DNSConfig
         log_dp.Info("配置自定义 DNSConfig", "内容", dnsConfig)
     } else {
         log_dp.Info("未配置 DNSConfig，使用 Kubernetes 默认 DNS 策略")
@@ -108,48 +167,63 @@ func NewDeployment(KubeApp *appsv1alpha1.KubeApp, namespace string) (*appsv1.Dep
 
     // volumes
     var volumes []corev1.Volume
-    if len(KubeApp.Spec.Deployment.Volumes) > 0 {
-        volumes = convertVolumesToK8sVolumes(KubeApp.Spec.Deployment.Volumes)
+    if len(spec.Volumes) > 0 {
+        volumes = convertVolumesToK8sVolumes(spec.Volumes)
         log_dp.Info("配置 Volumes", "数量", len(volumes))
     }
 
-    // 构建 Deployment 对象
-    deployment := &appsv1.Deployment{
+    // initContainers 与 sidecars
+    initContainers, containers := preparePodContainers(spec)
+
+    // 安全上下文：securityProfile 同时作用于主容器、init 容器和 sidecar
+    podSecurityContext := preparePodSecurityContext(spec.SecurityProfile)
+    if spec.SecurityProfile != nil {
+        for i := range initContainers {
+            initContainers[i].SecurityContext = prepareContainerSecurityContext(spec.SecurityProfile)
+        }
+        for i := range containers {
+            containers[i].SecurityContext = prepareContainerSecurityContext(spec.SecurityProfile)
+        }
+    }
+
+    // 托管 ConfigMap / Secret 的内容哈希，配置变化时触发滚动更新
+    var annotations map[string]string
+    if hash := ConfigHash(KubeApp); hash != "" {
+        annotations = map[string]string{appsv1alpha1.ConfigHashAnnotation: hash}
+        log_dp.Info("配置 Pod 模板配置哈希", "哈希", hash)
+    }
+
+    // serviceAccount：未配置时使用命名空间的 default ServiceAccount
+    var serviceAccountName string
+    var automountToken *bool
+    if sa := KubeApp.Spec.ServiceAccount; sa != nil {
+        serviceAccountName = ServiceAccountName(KubeApp)
+        automountToken = sa.AutomountServiceAccountToken
+        log_dp.Info("配置 ServiceAccount", "名称", serviceAccountName)
+    }
+
+    return corev1.PodTemplateSpec{
         ObjectMeta: metav1.ObjectMeta{
-            Name:        KubeApp.Spec.Deployment.Name,
-            Namespace:   namespace,
-            Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
-            Annotations: KubeApp.Annotations,
-        },
-        Spec: appsv1.DeploymentSpec{
-            Replicas: &replicas,
-            Selector: &metav1.LabelSelector{
-                MatchLabels: map[string]string{
-                    "app": KubeApp.Spec.Deployment.Name,
-                },
+            Labels: map[string]string{
+                "app": spec.Name,
             },
-            Template: corev1.PodTemplateSpec{
-                ObjectMeta: metav1.ObjectMeta{
-                    Labels: map[string]string{
-                        "app": KubeApp.Spec.Deployment.Name,
-                    },
-                },
-                Spec: corev1.PodSpec{
-                    Containers:                    []corev1.Container{prepareContainer(KubeApp.Spec.Deployment)},
-                    Volumes:                       volumes,
-                    NodeSelector:                  KubeApp.Spec.Deployment.NodeSelector,
-                    TerminationGracePeriodSeconds: terminationGracePeriodSeconds,
-                    ImagePullSecrets:              imagePullSecrets,
-                    Affinity:                       prepareAffinity(KubeApp.Spec.Deployment),
-                    DNSConfig:                     dnsConfig,
-                },
-            },
-            Strategy: prepareDeploymentStrategy(),
+            Annotations: annotations,
+        },
+        Spec: corev1.PodSpec{
+            InitContainers:                initContainers,
+            Containers:                    containers,
+            Volumes:                       volumes,
+            NodeSelector:                  spec.NodeSelector,
+            Tolerations:                   spec.Tolerations,
+            TerminationGracePeriodSeconds: terminationGracePeriodSeconds,
+            ImagePullSecrets:              imagePullSecrets,
+            Affinity:                      prepareAffinity(spec),
+            DNSConfig:                     dnsConfig,
+            ServiceAccountName:            serviceAccountName,
+            AutomountServiceAccountToken:  automountToken,
+            SecurityContext:               podSecurityContext,
         },
     }
-
-    log_dp.Info("Deployment 创建成功", "名称", deployment.Name, "命名空间", deployment.Namespace)
-    return deployment, nil
 }
 
 // validateDeploymentSpec 验证 Deployment 规格的必填字段
@@ -224,26 +298,81 @@ func prepareContainer(spec *appsv1alpha1.DeploymentSpec) corev1.Container {
 
     // 环境变量env
     container.Env = prepareEnv(spec)
-    // 添加安全上下文
-    // container.SecurityContext = prepareContainerSecurityContext()
 
     // 处理 VolumeMounts
-    if len(spec.VolumeMounts) > 0 {
-        var mounts []corev1.VolumeMount
-        for _, vm := range spec.VolumeMounts {
-            mounts = append(mounts, corev1.VolumeMount{
-                Name:      vm.Name,
-                MountPath: vm.MountPath,
-                ReadOnly:  vm.ReadOnly,
-            })
-            log_dp.V(1).Info("配置卷挂载", "容器名称", spec.Name, "卷名", vm.Name, "挂载路径", vm.MountPath, "只读", vm.ReadOnly)
+    container.VolumeMounts = convertVolumeMounts(spec.Name, spec.VolumeMounts)
+    return container
+}
+
+// preparePodContainers 组装 Pod 的 initContainers 与 containers。
+// restartPolicy 为 Always 的 sidecar 是原生 sidecar：放在 initContainers 最前面，
+// 保证普通 init 容器（例如依赖代理的数据库迁移）运行时它们已经启动；其余 sidecar 与主容器并列
+func preparePodContainers(spec *appsv1alpha1.DeploymentSpec) ([]corev1.Container, []corev1.Container) {
+    var initContainers []corev1.Container
+    containers := []corev1.Container{prepareContainer(spec)}
+
+    for i := range spec.Sidecars {
+        sidecar := prepareExtraContainer(&spec.Sidecars[i])
+        if isNativeSidecar(&spec.Sidecars[i]) {
+            log_dp.V(1).Info("配置原生 sidecar", "容器名称", sidecar.Name)
+            initContainers = append(initContainers, sidecar)
+            continue
         }
-        container.VolumeMounts = mounts
-        log_dp.V(1).Info("完成所有卷挂载配置", "容器名称", spec.Name, "挂载数量", len(mounts))
+        log_dp.V(1).Info("配置 sidecar", "容器名称", sidecar.Name)
+        containers = append(containers, sidecar)
+    }
+
+    for i := range spec.InitContainers {
+        initContainers = append(initContainers, prepareExtraContainer(&spec.InitContainers[i]))
+        log_dp.V(1).Info("配置 init 容器", "容器名称", spec.InitContainers[i].Name)
+    }
+    return initContainers, containers
+}
+
+// prepareExtraContainer 把 init 容器或 sidecar 的配置转换为 Kubernetes 容器
+func prepareExtraContainer(spec *appsv1alpha1.ContainerSpec) corev1.Container {
+    container := corev1.Container{
+        Name:           spec.Name,
+        Image:          spec.Image,
+        Command:        spec.Command,
+        Args:           spec.Args,
+        Env:            spec.Env,
+        Ports:          spec.Ports,
+        LivenessProbe:  spec.LivenessProbe,
+        ReadinessProbe: spec.ReadinessProbe,
+        StartupProbe:   spec.StartupProbe,
+        RestartPolicy:  spec.RestartPolicy,
+        VolumeMounts:   convertVolumeMounts(spec.Name, spec.VolumeMounts),
+    }
+    if spec.Resources != nil {
+        container.Resources = *spec.Resources
     }
     return container
 }
 
+// isNativeSidecar 判断容器是否以原生 sidecar（restartPolicy: Always 的 init 容器）运行
+func isNativeSidecar(spec *appsv1alpha1.ContainerSpec) bool {
+    return spec.RestartPolicy != nil && *spec.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+// convertVolumeMounts 把 KubeApp 的卷挂载转换为容器的卷挂载
+func convertVolumeMounts(containerName string, volumeMounts []appsv1alpha1.VolumeMount) []corev1.VolumeMount {
+    if len(volumeMounts) == 0 {
+        return nil
+    }
+    var mounts []corev1.VolumeMount
+    for _, vm := range volumeMounts {
+        mounts = append(mounts, corev1.VolumeMount{
+            Name:      vm.Name,
+            MountPath: vm.MountPath,
+            ReadOnly:  vm.ReadOnly,
+        })
+        log_dp.V(1).Info("配置卷挂载", "容器名称", containerName, "卷名", vm.Name, "挂载路径", vm.MountPath, "只读", vm.ReadOnly)
+    }
+    log_dp.V(1).Info("完成所有卷挂载配置", "容器名称", containerName, "挂载数量", len(mounts))
+    return mounts
+}
+
 
 // prepareAffinity 处理 Affinity 设置逻辑
 
@@ -270,52 +399,130 @@ func prepareEnv(spec *appsv1alpha1.DeploymentSpec) []corev1.EnvVar {
 }
 
 
-// preparePodSecurityContext 设置 Pod 安全上下文
-func _preparePodSecurityContext() *corev1.PodSecurityContext {
-    log_dp.V(1).Info("配置 Pod 安全上下文")
-    return &corev1.PodSecurityContext{
-        RunAsNonRoot: boolPtr(true),
+// preparePodSecurityContext 按 securityProfile 设置 Pod 安全上下文，未配置时返回 nil
+func preparePodSecurityContext(profile *appsv1alpha1.SecurityProfileSpec) *corev1.PodSecurityContext {
+    if profile == nil {
+        return nil
+    }
+    log_dp.V(1).Info("配置 Pod 安全上下文", "securityProfile", profile.Type)
+    runtimeDefault := &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+    switch profile.Type {
+    case appsv1alpha1.SecurityProfileRestricted:
+        return &corev1.PodSecurityContext{
+            RunAsNonRoot:   boolPtr(true),
+            SeccompProfile: runtimeDefault,
+        }
+    case appsv1alpha1.SecurityProfileBaseline:
+        return &corev1.PodSecurityContext{SeccompProfile: runtimeDefault}
+    default:
+        return profile.PodSecurityContext.DeepCopy()
     }
 }
 
+// prepareContainerSecurityContext 按 securityProfile 设置容器安全上下文，未配置时返回 nil。
+// restricted 不强制 readOnlyRootFilesystem：PSA 不要求，而且多数镜像需要写 /tmp，需要时使用 custom
+func prepareContainerSecurityContext(profile *appsv1alpha1.SecurityProfileSpec) *corev1.SecurityContext {
+    if profile == nil {
+        return nil
+    }
+    log_dp.V(1).Info("配置容器安全上下文", "securityProfile", profile.Type)
+    switch profile.Type {
+    case appsv1alpha1.SecurityProfileRestricted:
+        return &corev1.SecurityContext{
+            AllowPrivilegeEscalation: boolPtr(false),
+            Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+        }
+    case appsv1alpha1.SecurityProfileBaseline:
+        return &corev1.SecurityContext{
+            AllowPrivilegeEscalation: boolPtr(false),
+            Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"NET_RAW"}},
+        }
+    default:
+        return profile.ContainerSecurityContext.DeepCopy()
+    }
+}
 
 
-// prepareContainerSecurityContext 设置容器安全上下文
-func _prepareContainerSecurityContext() *corev1.SecurityContext {
-    log_dp.V(1).Info("配置容器安全上下文")
-    return &corev1.SecurityContext{
-        AllowPrivilegeEscalation: boolPtr(false),
-        ReadOnlyRootFilesystem:   boolPtr(true),
+// DefaultMaxSurge / DefaultMaxUnavailable RollingUpdate 未指定时使用的默认值，与 Kubernetes 一致按百分比计算
+var (
+    DefaultMaxSurge       = intstr.FromString("25%")
+    DefaultMaxUnavailable = intstr.FromString("25%")
+)
+
+// deploymentStrategyType 返回生效的部署策略，未设置时为 RollingUpdate
+func deploymentStrategyType(strategy *appsv1alpha1.DeploymentStrategySpec) appsv1.DeploymentStrategyType {
+    if strategy == nil || strategy.Type == "" {
+        return appsv1.RollingUpdateDeploymentStrategyType
     }
+    return strategy.Type
 }
 
-// prepareDeploymentStrategy 配置部署策略
-func prepareDeploymentStrategy() appsv1.DeploymentStrategy {
-    log_dp.V(1).Info("配置部署策略")
+// prepareDeploymentStrategy 配置部署策略：Recreate 不带滚动参数，RollingUpdate 的 maxSurge / maxUnavailable 默认 25%
+func prepareDeploymentStrategy(strategy *appsv1alpha1.DeploymentStrategySpec) appsv1.DeploymentStrategy {
+    strategyType := deploymentStrategyType(strategy)
+    log_dp.V(1).Info("配置部署策略", "策略", strategyType)
+    if strategyType == appsv1.RecreateDeploymentStrategyType {
+        return appsv1.DeploymentStrategy{Type: strategyType}
+    }
+
+    maxSurge, maxUnavailable := DefaultMaxSurge, DefaultMaxUnavailable
+    if strategy != nil && strategy.MaxSurge != nil {
+        maxSurge = *strategy.MaxSurge
+    }
+    if strategy != nil && strategy.MaxUnavailable != nil {
+        maxUnavailable = *strategy.MaxUnavailable
+    }
     return appsv1.DeploymentStrategy{
-        Type: appsv1.RollingUpdateDeploymentStrategyType,
+        Type: strategyType,
         RollingUpdate: &appsv1.RollingUpdateDeployment{
-            MaxUnavailable: intOrStrPtr(25),
-            MaxSurge:       intOrStrPtr(25),
+            MaxUnavailable: &maxUnavailable,
+            MaxSurge:       &maxSurge,
         },
     }
 }
 
+// validateDeploymentStrategy 校验部署策略：Recreate 不能配置滚动参数，maxSurge 与 maxUnavailable 不能同时为 0，
+// progressDeadlineSeconds 必须大于 minReadySeconds
+func validateDeploymentStrategy(strategy *appsv1alpha1.DeploymentStrategySpec) error {
+    if strategy == nil {
+        return nil
+    }
+    if deploymentStrategyType(strategy) == appsv1.RecreateDeploymentStrategyType {
+        if strategy.MaxSurge != nil || strategy.MaxUnavailable != nil {
+            return fmt.Errorf("maxSurge / maxUnavailable 只能用于 RollingUpdate 策略")
+        }
+    } else {
+        rolling := prepareDeploymentStrategy(strategy).RollingUpdate
+        if err := validateIntOrPercent("maxSurge", *rolling.MaxSurge, true); err != nil {
+            return err
+        }
+        if err := validateIntOrPercent("maxUnavailable", *rolling.MaxUnavailable, true); err != nil {
+            return err
+        }
+        surge, _ := intstr.GetScaledValueFromIntOrPercent(rolling.MaxSurge, 100, true)
+        unavailable, _ := intstr.GetScaledValueFromIntOrPercent(rolling.MaxUnavailable, 100, false)
+        if surge == 0 && unavailable == 0 {
+            return fmt.Errorf("maxSurge 和 maxUnavailable 不能同时为 0")
+        }
+    }
+    if strategy.MinReadySeconds < 0 {
+        return fmt.Errorf("minReadySeconds 不能为负数，当前为 %d", strategy.MinReadySeconds)
+    }
+    if strategy.RevisionHistoryLimit != nil && *strategy.RevisionHistoryLimit < 0 {
+        return fmt.Errorf("revisionHistoryLimit 不能为负数，当前为 %d", *strategy.RevisionHistoryLimit)
+    }
+    if d := strategy.ProgressDeadlineSeconds; d != nil && *d <= strategy.MinReadySeconds {
+        return fmt.Errorf("progressDeadlineSeconds (%d) 必须大于 minReadySeconds (%d)", *d, strategy.MinReadySeconds)
+    }
+    return nil
+}
+
 
 // 辅助函数：创建布尔指针
 func boolPtr(b bool) *bool {
     return &b
 }
 
-// 辅助函数：创建整数或字符串指针
-func intOrStrPtr(val int) *intstr.IntOrString {
-    return &intstr.IntOrString{
-        Type:   intstr.Int,
-        IntVal: int32(val),
-    }
-}
-
-
 // Volumes auth tyoe logic her 自动识别卷类型不需要用户指定
 
 func convertVolumesToK8sVolumes(volumeConfigs []appsv1alpha1.VolumeConfig) []corev1.Volume {
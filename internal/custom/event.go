@@ -0,0 +1,95 @@
+package define
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// EventInfo 是 /kube/event/query 返回的一行，列与 kubectl get events 对应
+type EventInfo struct {
+	Namespace string `json:"namespace"`
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Object    string `json:"object"`
+	Message   string `json:"message"`
+	Source    string `json:"source"`
+	Count     int32  `json:"count"`
+	FirstSeen string `json:"first_seen"`
+	LastSeen  string `json:"last_seen"`
+	Age       string `json:"age"`
+}
+
+// ListEvents 查询指定命名空间下的 Event，kind / name 不为空时只返回该对象的事件，按最近发生时间倒序
+func ListEvents(namespace, kind, name string) ([]EventInfo, error) {
+	if GlobalClient == nil {
+		return nil, fmt.Errorf("k8s client 未初始化")
+	}
+
+	var eventList corev1.EventList
+	if err := GlobalClient.List(context.Background(), &eventList, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+
+	events := eventList.Items
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventLastSeen(&events[i]).After(eventLastSeen(&events[j]))
+	})
+
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	var result []EventInfo
+	for i := range events {
+		e := &events[i]
+		obj := e.InvolvedObject
+		if (kind != "" && obj.Kind != kind) || (name != "" && obj.Name != name) {
+			continue
+		}
+
+		firstSeen := e.FirstTimestamp.Time
+		if firstSeen.IsZero() {
+			firstSeen = e.CreationTimestamp.Time
+		}
+		lastSeen := eventLastSeen(e)
+		count := e.Count
+		if count == 0 {
+			count = 1
+		}
+		source := e.Source.Component
+		if source == "" {
+			source = e.ReportingController
+		}
+		result = append(result, EventInfo{
+			Namespace: e.Namespace,
+			Type:      e.Type,
+			Reason:    e.Reason,
+			Kind:      obj.Kind,
+			Name:      obj.Name,
+			Object:    fmt.Sprintf("%s/%s", obj.Kind, obj.Name),
+			Message:   e.Message,
+			Source:    source,
+			Count:     count,
+			FirstSeen: firstSeen.In(loc).Format("2006-01-02 15:04:05"),
+			LastSeen:  lastSeen.In(loc).Format("2006-01-02 15:04:05"),
+			Age:       utils.FormatAge(lastSeen),
+		})
+	}
+	return result, nil
+}
+
+// eventLastSeen 返回事件最近一次发生的时间。events.k8s.io 写入的事件只有 eventTime，没有 lastTimestamp
+func eventLastSeen(e *corev1.Event) time.Time {
+	switch {
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
@@ -0,0 +1,132 @@
+package define
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_hpa = logf.Log.WithName("hpa-creator")
+
+// AutoscalingEnabled 判断 KubeApp 是否为工作负载生成 HPA。
+// 只有 Deployment 和 StatefulSet 支持扩缩容，其他类型的 autoscaling 在准入阶段被拒绝
+func AutoscalingEnabled(KubeApp *appsv1alpha1.KubeApp) bool {
+	if !KubeApp.Spec.EnableDeployment || KubeApp.Spec.Deployment == nil || KubeApp.Spec.Autoscaling == nil {
+		return false
+	}
+	switch KubeApp.Spec.EffectiveWorkloadType() {
+	case appsv1alpha1.WorkloadDeployment, appsv1alpha1.WorkloadStatefulSet:
+		return true
+	}
+	return false
+}
+
+// DeleteHorizontalPodAutoscaler 删除 KubeApp 对应的 HPA，同名但不由该 KubeApp 控制的 HPA 会被保留
+func DeleteHorizontalPodAutoscaler(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{}
+	hpa.SetName(DeploymentName(KubeApp))
+	hpa.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, hpa, KubeApp)
+}
+
+// NewHorizontalPodAutoscaler 根据 spec.autoscaling 创建 autoscaling/v2 HPA，扩缩容目标是同名的 Deployment 或 StatefulSet
+func NewHorizontalPodAutoscaler(KubeApp *appsv1alpha1.KubeApp, namespace string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	if KubeApp == nil || KubeApp.Spec.Deployment == nil {
+		return nil, fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+	spec := KubeApp.Spec.Autoscaling
+	if spec == nil {
+		return nil, fmt.Errorf("KubeApp 未配置 autoscaling")
+	}
+
+	minReplicas := autoscalingMinReplicas(KubeApp)
+	if err := validateAutoscalingSpec(spec, minReplicas); err != nil {
+		log_hpa.Error(err, "HPA 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	log_hpa.Info("开始创建 HPA", "KubeApp名称", KubeApp.Name, "命名空间", namespace,
+		"minReplicas", minReplicas, "maxReplicas", spec.MaxReplicas)
+
+	kind := "Deployment"
+	if KubeApp.Spec.EffectiveWorkloadType() == appsv1alpha1.WorkloadStatefulSet {
+		kind = "StatefulSet"
+	}
+
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		TypeMeta: metav1.TypeMeta{APIVersion: autoscalingv2.SchemeGroupVersion.String(), Kind: "HorizontalPodAutoscaler"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        DeploymentName(KubeApp),
+			Namespace:   namespace,
+			Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+			Annotations: childAnnotations(KubeApp),
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: appsv1.SchemeGroupVersion.String(),
+				Kind:       kind,
+				Name:       DeploymentName(KubeApp),
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: spec.MaxReplicas,
+			Metrics:     prepareAutoscalingMetrics(spec),
+			Behavior:    spec.Behavior,
+		},
+	}
+
+	log_hpa.Info("HPA 创建成功", "名称", hpa.Name, "命名空间", namespace, "目标", kind, "指标数量", len(hpa.Spec.Metrics))
+	return hpa, nil
+}
+
+// autoscalingMinReplicas 返回生效的最小副本数，未设置时使用 deployment.replicas
+func autoscalingMinReplicas(KubeApp *appsv1alpha1.KubeApp) int32 {
+	if spec := KubeApp.Spec.Autoscaling; spec != nil && spec.MinReplicas != nil {
+		return *spec.MinReplicas
+	}
+	return prepareReplicas(KubeApp)
+}
+
+// prepareAutoscalingMetrics 把 CPU / 内存利用率目标转换为 Resource 指标，再追加自定义指标
+func prepareAutoscalingMetrics(spec *appsv1alpha1.AutoscalingSpec) []autoscalingv2.MetricSpec {
+	var metrics []autoscalingv2.MetricSpec
+	resourceMetric := func(name corev1.ResourceName, utilization int32) autoscalingv2.MetricSpec {
+		return autoscalingv2.MetricSpec{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricSource{
+				Name: name,
+				Target: autoscalingv2.MetricTarget{
+					Type:               autoscalingv2.UtilizationMetricType,
+					AverageUtilization: &utilization,
+				},
+			},
+		}
+	}
+	if spec.TargetCPUUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceCPU, *spec.TargetCPUUtilizationPercentage))
+	}
+	if spec.TargetMemoryUtilizationPercentage != nil {
+		metrics = append(metrics, resourceMetric(corev1.ResourceMemory, *spec.TargetMemoryUtilizationPercentage))
+	}
+	metrics = append(metrics, spec.Metrics...)
+	log_hpa.V(1).Info("配置 HPA 指标", "数量", len(metrics))
+	return metrics
+}
+
+// validateAutoscalingSpec 校验副本范围：maxReplicas 不能小于 minReplicas
+func validateAutoscalingSpec(spec *appsv1alpha1.AutoscalingSpec, minReplicas int32) error {
+	if spec.MaxReplicas < 1 {
+		return fmt.Errorf("maxReplicas 必须大于 0，当前为 %d", spec.MaxReplicas)
+	}
+	if spec.MaxReplicas < minReplicas {
+		return fmt.Errorf("maxReplicas(%d) 不能小于 minReplicas(%d)", spec.MaxReplicas, minReplicas)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package define
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_route = logf.Log.WithName("httproute-creator")
+
+// HTTPRouteGVK Gateway API 的 HTTPRoute 类型。operator 不依赖 gateway-api 模块，以 unstructured 构建
+var HTTPRouteGVK = schema.GroupVersionKind{
+	Group:   "gateway.networking.k8s.io",
+	Version: "v1",
+	Kind:    "HTTPRoute",
+}
+
+// Gateway API 中路径、请求头匹配方式的默认值
+const (
+	defaultRoutePathType   = "PathPrefix"
+	defaultHeaderMatchType = "Exact"
+)
+
+// GatewayRouteConfigured 判断 KubeApp 是否配置了 spec.routing.gateway
+func GatewayRouteConfigured(KubeApp *appsv1alpha1.KubeApp) bool {
+	return KubeApp.Spec.Routing != nil && KubeApp.Spec.Routing.Gateway != nil
+}
+
+// DeleteHTTPRoute 删除由 KubeApp 控制的 HTTPRoute，同名的其他 HTTPRoute 会被保留。集群未安装 Gateway API 时不存在可删除的对象，直接返回
+func DeleteHTTPRoute(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName(HTTPRouteName(KubeApp))
+	route.SetNamespace(namespace)
+	if err := utils.DeleteIfControlled(ctx, cli, route, KubeApp); err != nil && !meta.IsNoMatchError(err) {
+		return err
+	}
+	return nil
+}
+
+// NewHTTPRoute 根据 spec.routing.gateway 创建 gateway.networking.k8s.io/v1 HTTPRoute
+func NewHTTPRoute(KubeApp *appsv1alpha1.KubeApp, namespace string) (*unstructured.Unstructured, error) {
+	if KubeApp == nil || !GatewayRouteConfigured(KubeApp) {
+		return nil, fmt.Errorf("KubeApp 未配置 routing.gateway")
+	}
+	if err := validateGatewayRoute(KubeApp); err != nil {
+		log_route.Error(err, "HTTPRoute 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	spec := KubeApp.Spec.Routing.Gateway
+	log_route.Info("开始创建 HTTPRoute", "KubeApp名称", KubeApp.Name, "命名空间", namespace)
+
+	route := &unstructured.Unstructured{}
+	route.SetGroupVersionKind(HTTPRouteGVK)
+	route.SetName(HTTPRouteName(KubeApp))
+	route.SetNamespace(namespace)
+	route.SetLabels(utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}))
+	if annotations := utils.MergeMaps(childAnnotations(KubeApp), spec.Annotations); len(annotations) > 0 {
+		route.SetAnnotations(annotations)
+	}
+
+	routeSpec := map[string]interface{}{
+		"parentRefs": prepareParentRefs(spec.ParentRefs),
+		"rules":      prepareRouteRules(EffectiveHTTPRouteRules(KubeApp)),
+	}
+	if len(spec.Hostnames) > 0 {
+		hostnames := make([]interface{}, 0, len(spec.Hostnames))
+		for _, h := range spec.Hostnames {
+			hostnames = append(hostnames, h)
+		}
+		routeSpec["hostnames"] = hostnames
+	}
+	route.Object["spec"] = routeSpec
+
+	log_route.Info("HTTPRoute 创建成功", "名称", route.GetName(), "命名空间", namespace,
+		"parentRefs", len(spec.ParentRefs), "rules", len(routeSpec["rules"].([]interface{})))
+	return route, nil
+}
+
+// EffectiveHTTPRouteRules 返回生效的路由规则：未配置 rules 时把所有请求转发到 spec.service，
+// 未指定后端名称或端口时使用 spec.service 的名称和第一个端口
+func EffectiveHTTPRouteRules(KubeApp *appsv1alpha1.KubeApp) []appsv1alpha1.HTTPRouteRule {
+	rules := KubeApp.Spec.Routing.Gateway.Rules
+	if len(rules) == 0 {
+		rules = []appsv1alpha1.HTTPRouteRule{{}}
+	}
+
+	out := make([]appsv1alpha1.HTTPRouteRule, 0, len(rules))
+	for _, r := range rules {
+		rule := *r.DeepCopy()
+		if len(rule.BackendRefs) == 0 {
+			rule.BackendRefs = []appsv1alpha1.HTTPBackendRef{{}}
+		}
+		for i := range rule.BackendRefs {
+			backend := &rule.BackendRefs[i]
+			if backend.Name == "" {
+				backend.Name = ServiceName(KubeApp)
+			}
+			if backend.Port == 0 && backend.Name == ServiceName(KubeApp) && KubeApp.Spec.Service != nil {
+				backend.Port = ServicePortNumbers(KubeApp.Spec.Service)[0]
+			}
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+// prepareParentRefs 构建 parentRefs，显式写出 group/kind 以免与 API server 默认值产生差异
+func prepareParentRefs(refs []appsv1alpha1.GatewayParentRef) []interface{} {
+	out := make([]interface{}, 0, len(refs))
+	for _, ref := range refs {
+		parent := map[string]interface{}{
+			"group": HTTPRouteGVK.Group,
+			"kind":  "Gateway",
+			"name":  ref.Name,
+		}
+		if ref.Namespace != "" {
+			parent["namespace"] = ref.Namespace
+		}
+		if ref.SectionName != "" {
+			parent["sectionName"] = ref.SectionName
+		}
+		if ref.Port != nil {
+			parent["port"] = int64(*ref.Port)
+		}
+		out = append(out, parent)
+	}
+	return out
+}
+
+// prepareRouteRules 把路由规则转换为 HTTPRoute 的 rules 字段
+func prepareRouteRules(rules []appsv1alpha1.HTTPRouteRule) []interface{} {
+	out := make([]interface{}, 0, len(rules))
+	for _, r := range rules {
+		rule := map[string]interface{}{}
+
+		var matches []interface{}
+		for _, m := range r.Matches {
+			match := map[string]interface{}{
+				"path": map[string]interface{}{
+					"type":  routePathType(m.PathType),
+					"value": normalizePath(m.Path),
+				},
+			}
+			var headers []interface{}
+			for _, h := range m.Headers {
+				headers = append(headers, map[string]interface{}{
+					"type":  headerMatchType(h.Type),
+					"name":  h.Name,
+					"value": h.Value,
+				})
+			}
+			if len(headers) > 0 {
+				match["headers"] = headers
+			}
+			matches = append(matches, match)
+		}
+		if len(matches) > 0 {
+			rule["matches"] = matches
+		}
+
+		var backends []interface{}
+		for _, b := range r.BackendRefs {
+			backend := map[string]interface{}{
+				"group": "",
+				"kind":  "Service",
+				"name":  b.Name,
+				"port":  int64(b.Port),
+			}
+			if b.Weight != nil {
+				backend["weight"] = int64(*b.Weight)
+			}
+			backends = append(backends, backend)
+		}
+		rule["backendRefs"] = backends
+
+		out = append(out, rule)
+	}
+	return out
+}
+
+// routePathType 返回路径匹配方式，未设置时为 PathPrefix
+func routePathType(pathType string) string {
+	if pathType == "" {
+		return defaultRoutePathType
+	}
+	return pathType
+}
+
+// headerMatchType 返回请求头匹配方式，未设置时为 Exact
+func headerMatchType(matchType string) string {
+	if matchType == "" {
+		return defaultHeaderMatchType
+	}
+	return matchType
+}
+
+// validateGatewayRoute 校验 parentRefs、hostnames、匹配条件和后端。
+// 后端端口是否为 Service 暴露的端口在准入阶段结合整个 spec 校验
+func validateGatewayRoute(KubeApp *appsv1alpha1.KubeApp) error {
+	spec := KubeApp.Spec.Routing.Gateway
+	if spec.Name != "" {
+		if errs := validation.IsDNS1123Subdomain(spec.Name); len(errs) > 0 {
+			return fmt.Errorf("HTTPRoute 名称 %q 不合法: %s", spec.Name, strings.Join(errs, ", "))
+		}
+	}
+
+	if len(spec.ParentRefs) == 0 {
+		return fmt.Errorf("HTTPRoute 至少需要一个 parentRef")
+	}
+	for _, ref := range spec.ParentRefs {
+		if ref.Name == "" {
+			return fmt.Errorf("parentRef 的 name 不能为空")
+		}
+		if ref.Namespace != "" {
+			if errs := validation.IsDNS1123Label(ref.Namespace); len(errs) > 0 {
+				return fmt.Errorf("parentRef %s 的 namespace %q 不合法: %s", ref.Name, ref.Namespace, strings.Join(errs, ", "))
+			}
+		}
+		if ref.SectionName != "" {
+			if errs := validation.IsDNS1123Subdomain(ref.SectionName); len(errs) > 0 {
+				return fmt.Errorf("parentRef %s 的 sectionName %q 不合法: %s", ref.Name, ref.SectionName, strings.Join(errs, ", "))
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, h := range spec.Hostnames {
+		if err := validateHost(h); err != nil {
+			return fmt.Errorf("HTTPRoute hostname %q 验证失败: %v", h, err)
+		}
+		if seen[h] {
+			return fmt.Errorf("HTTPRoute hostname %s 重复", h)
+		}
+		seen[h] = true
+	}
+
+	for _, r := range spec.Rules {
+		for _, m := range r.Matches {
+			if pt := routePathType(m.PathType); pt != "RegularExpression" && !strings.HasPrefix(normalizePath(m.Path), "/") {
+				return fmt.Errorf("HTTPRoute path %q 必须以 / 开头", m.Path)
+			}
+			for _, h := range m.Headers {
+				if errs := validation.IsHTTPHeaderName(h.Name); len(errs) > 0 {
+					return fmt.Errorf("请求头名称 %q 不合法: %s", h.Name, strings.Join(errs, ", "))
+				}
+			}
+		}
+	}
+
+	for _, r := range EffectiveHTTPRouteRules(KubeApp) {
+		for _, b := range r.BackendRefs {
+			if b.Port == 0 {
+				return fmt.Errorf("HTTPRoute 后端 %s 必须指定 port（只有 spec.service 可以省略）", b.Name)
+			}
+			if err := utils.ValidatePort(b.Port); err != nil {
+				return fmt.Errorf("HTTPRoute 后端 %s 端口验证失败: %v", b.Name, err)
+			}
+		}
+	}
+	return nil
+}
@@ -52,17 +52,13 @@ var (
 
 
 
-// DeleteIngress 删除 Ingress（当 EnableIngress == false 时调用）
+// DeleteIngress 删除 Ingress（当 EnableIngress == false 时调用），不由该 KubeApp 控制的同名 Ingress 会被保留
 func DeleteIngress(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
-    name := KubeApp.Name
-    if KubeApp.Spec.Ingress != nil && KubeApp.Spec.Ingress.Name != "" {
-        name = KubeApp.Spec.Ingress.Name
-    }
     ing := &networkingv1.Ingress{}
-    ing.SetName(name)
+    ing.SetName(IngressName(KubeApp))
     ing.SetNamespace(namespace)
 
-    return utils.DeleteIfExists(ctx, cli, ing)
+    return utils.DeleteIfControlled(ctx, cli, ing, KubeApp)
 }
 
 
@@ -94,29 +90,8 @@ func NewIngress(KubeApp *appsv1alpha1.KubeApp, namespace string) (*networkingv1.
 
     // 3. 构建 IngressSpec（暂不设置 ingressClassName）
     ingressSpec := networkingv1.IngressSpec{
-        Rules: []networkingv1.IngressRule{
-            {
-                Host: normalizeHost(KubeApp.Spec.Ingress.Host),
-                IngressRuleValue: networkingv1.IngressRuleValue{
-                    HTTP: &networkingv1.HTTPIngressRuleValue{
-                        Paths: []networkingv1.HTTPIngressPath{
-                            {
-                                Path:     normalizePath(KubeApp.Spec.Ingress.Path),
-                                PathType: getPathType(KubeApp.Spec.Ingress.PathType),
-                                Backend: networkingv1.IngressBackend{
-                                    Service: &networkingv1.IngressServiceBackend{
-                                        Name: KubeApp.Spec.Ingress.ServiceName,
-                                        Port: networkingv1.ServiceBackendPort{
-                                            Number: utils.NormalizePort(KubeApp.Spec.Ingress.ServicePort),
-                                        },
-                                    },
-                                },
-                            },
-                        },
-                    },
-                },
-            },
-        },
+        Rules: prepareIngressRules(KubeApp.Spec.Ingress),
+        TLS:   prepareIngressTLS(KubeApp.Spec.Ingress),
     }
 
     // 4. 处理 ingressClassName（仅当显式指定时）
@@ -130,12 +105,14 @@ func NewIngress(KubeApp *appsv1alpha1.KubeApp, namespace string) (*networkingv1.
     }
 
     // 5. 注解处理：保持字段和注解一致性（清洗用户注解）
-    cleanedAnnotations := sanitizeIngressAnnotations(KubeApp.Annotations, cls)
+    // spec.ingress.annotations 覆盖从 KubeApp 继承的同名注解
+    cleanedAnnotations := sanitizeIngressAnnotations(utils.MergeMaps(childAnnotations(KubeApp), KubeApp.Spec.Ingress.Annotations), cls)
 
     // 6. 构建 Ingress 对象
     ingress := &networkingv1.Ingress{
+        TypeMeta: metav1.TypeMeta{APIVersion: networkingv1.SchemeGroupVersion.String(), Kind: "Ingress"},
         ObjectMeta: metav1.ObjectMeta{
-            Name:        KubeApp.Name,
+            Name:        IngressName(KubeApp),
             Namespace:   namespace,
             Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
             Annotations: cleanedAnnotations,
@@ -143,10 +120,71 @@ func NewIngress(KubeApp *appsv1alpha1.KubeApp, namespace string) (*networkingv1.
         Spec: ingressSpec,
     }
 
-    log_ing.Info("Ingress 创建成功", "名称", ingress.Name, "命名空间", ingress.Namespace)
+    log_ing.Info("Ingress 创建成功", "名称", ingress.Name, "命名空间", ingress.Namespace,
+        "规则数量", len(ingressSpec.Rules), "TLS数量", len(ingressSpec.TLS))
     return ingress, nil
 }
 
+// EffectiveIngressRules 返回生效的路由规则：配置了 rules 时直接使用，
+// 否则把单路由写法 host / path / serviceName / servicePort 转换为一条规则
+func EffectiveIngressRules(spec *appsv1alpha1.IngressSpec) []appsv1alpha1.IngressRule {
+    if len(spec.Rules) > 0 {
+        return spec.Rules
+    }
+    return []appsv1alpha1.IngressRule{
+        {
+            Host: normalizeHost(spec.Host),
+            Paths: []appsv1alpha1.IngressPath{
+                {
+                    Path:        spec.Path,
+                    PathType:    spec.PathType,
+                    ServiceName: spec.ServiceName,
+                    ServicePort: spec.ServicePort,
+                },
+            },
+        },
+    }
+}
+
+// prepareIngressRules 把生效的路由规则转换为 networking/v1 规则，每个 path 指向各自的 Service 端口
+func prepareIngressRules(spec *appsv1alpha1.IngressSpec) []networkingv1.IngressRule {
+    var rules []networkingv1.IngressRule
+    for _, r := range EffectiveIngressRules(spec) {
+        var paths []networkingv1.HTTPIngressPath
+        for _, p := range r.Paths {
+            paths = append(paths, networkingv1.HTTPIngressPath{
+                Path:     normalizePath(p.Path),
+                PathType: getPathType(p.PathType),
+                Backend: networkingv1.IngressBackend{
+                    Service: &networkingv1.IngressServiceBackend{
+                        Name: p.ServiceName,
+                        Port: networkingv1.ServiceBackendPort{
+                            Number: utils.NormalizePort(p.ServicePort),
+                        },
+                    },
+                },
+            })
+        }
+        rules = append(rules, networkingv1.IngressRule{
+            Host: r.Host,
+            IngressRuleValue: networkingv1.IngressRuleValue{
+                HTTP: &networkingv1.HTTPIngressRuleValue{Paths: paths},
+            },
+        })
+    }
+    log_ing.V(1).Info("配置 Ingress 规则", "数量", len(rules))
+    return rules
+}
+
+// prepareIngressTLS 构建 TLS 配置，未设置 secretName 时由 Ingress Controller 使用默认证书
+func prepareIngressTLS(spec *appsv1alpha1.IngressSpec) []networkingv1.IngressTLS {
+    var tls []networkingv1.IngressTLS
+    for _, t := range spec.TLS {
+        tls = append(tls, networkingv1.IngressTLS{Hosts: t.Hosts, SecretName: t.SecretName})
+    }
+    return tls
+}
+
 
 // 冲突处理逻辑
 // sanitizeIngressAnnotations 处理注解与 ingressClassName 的一致性
@@ -215,21 +253,76 @@ func validateIngressSpec(ingressSpec *appsv1alpha1.IngressSpec) error {
         return fmt.Errorf("Ingress 规格不能为空")
     }
 
-    // 验证 Host
-    if err := validateHost(ingressSpec.Host); err != nil {
-        return fmt.Errorf("Ingress Host 验证失败: %v", err)
-    }
+    if len(ingressSpec.Rules) == 0 {
+        // 验证 Host
+        if err := validateHost(ingressSpec.Host); err != nil {
+            return fmt.Errorf("Ingress Host 验证失败: %v", err)
+        }
 
-    // 验证 ServiceName
-    if ingressSpec.ServiceName == "" {
-        return fmt.Errorf("Ingress ServiceName 不能为空")
+        // 验证 ServiceName
+        if ingressSpec.ServiceName == "" {
+            return fmt.Errorf("Ingress ServiceName 不能为空")
+        }
+
+        // 验证 ServicePort
+        if err := utils.ValidatePort(ingressSpec.ServicePort); err != nil {
+            return fmt.Errorf("Ingress ServicePort 验证失败: %v", err)
+        }
+    } else if err := validateIngressRules(ingressSpec.Rules); err != nil {
+        return err
     }
 
-    // 验证 ServicePort
-    if err := utils.ValidatePort(ingressSpec.ServicePort); err != nil {
-        return fmt.Errorf("Ingress ServicePort 验证失败: %v", err)
+    return validateIngressTLS(ingressSpec.TLS)
+}
+
+// validateIngressRules 校验多规则配置：host 合法，每条规则至少一个 path，
+// path 以 / 开头且同一 host 下不重复，后端 Service 名称和端口合法
+func validateIngressRules(rules []appsv1alpha1.IngressRule) error {
+    seen := map[string]bool{}
+    for _, r := range rules {
+        if err := validateHost(r.Host); err != nil {
+            return fmt.Errorf("Ingress Host %q 验证失败: %v", r.Host, err)
+        }
+        if len(r.Paths) == 0 {
+            return fmt.Errorf("Ingress Host %s 至少需要一个 path", r.Host)
+        }
+        for _, p := range r.Paths {
+            path := normalizePath(p.Path)
+            if !strings.HasPrefix(path, "/") {
+                return fmt.Errorf("Ingress path %q 必须以 / 开头", p.Path)
+            }
+            key := r.Host + path
+            if seen[key] {
+                return fmt.Errorf("Ingress 路由 %s 重复", key)
+            }
+            seen[key] = true
+
+            if p.ServiceName == "" {
+                return fmt.Errorf("Ingress 路由 %s 的 serviceName 不能为空", key)
+            }
+            if err := utils.ValidatePort(p.ServicePort); err != nil {
+                return fmt.Errorf("Ingress 路由 %s 的 servicePort 验证失败: %v", key, err)
+            }
+        }
     }
+    return nil
+}
 
+// validateIngressTLS 校验 TLS 配置：hosts 不能为空且格式合法，secretName 为合法的资源名称
+func validateIngressTLS(tls []appsv1alpha1.IngressTLS) error {
+    for _, t := range tls {
+        if len(t.Hosts) == 0 {
+            return fmt.Errorf("Ingress TLS 的 hosts 不能为空")
+        }
+        for _, host := range t.Hosts {
+            if err := validateHost(host); err != nil {
+                return fmt.Errorf("Ingress TLS Host %q 验证失败: %v", host, err)
+            }
+        }
+        if t.SecretName != "" && !rfc1123SubdomainRegex.MatchString(t.SecretName) {
+            return fmt.Errorf("Ingress TLS secretName %q 不合法，必须符合 RFC 1123 子域名格式", t.SecretName)
+        }
+    }
     return nil
 }
 
@@ -239,6 +332,9 @@ func validateHost(host string) error {
         return fmt.Errorf("主机名不能为空")
     }
 
+    // 通配符域名只允许出现在最左侧，例如 *.example.com
+    host = strings.TrimPrefix(host, "*.")
+
     // 使用 URL 解析验证主机名
     u, err := url.Parse("http://" + host)
     if err != nil {
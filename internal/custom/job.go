@@ -0,0 +1,150 @@
+package define
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_job = logf.Log.WithName("job-creator")
+
+// DeleteJob 删除 KubeApp 对应的 Job，Pod 随后由垃圾回收清理
+func DeleteJob(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	job := &batchv1.Job{}
+	job.SetName(DeploymentName(KubeApp))
+	job.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, job, KubeApp)
+}
+
+// DeleteCronJob 删除 KubeApp 对应的 CronJob
+func DeleteCronJob(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	cronJob := &batchv1.CronJob{}
+	cronJob.SetName(DeploymentName(KubeApp))
+	cronJob.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, cronJob, KubeApp)
+}
+
+// NewJob 根据 KubeApp 创建一次性 Job，Pod 模板与 Deployment 使用同一套构建逻辑。
+// Job 的模板创建后不可修改，因此在注解中记录 spec 哈希，供 controller 判断是否需要重建
+func NewJob(KubeApp *appsv1alpha1.KubeApp, namespace string) (*batchv1.Job, error) {
+	if err := validateBatchParams(KubeApp); err != nil {
+		log_job.Error(err, "Job 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	log_job.Info("开始创建 Job", "KubeApp名称", KubeApp.Name, "命名空间", namespace)
+
+	jobSpec := prepareJobSpec(KubeApp)
+	hash, err := specHash(jobSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := workloadObjectMeta(KubeApp, namespace)
+	meta.Annotations = utils.MergeMaps(meta.Annotations, map[string]string{appsv1alpha1.SpecHashAnnotation: hash})
+	job := &batchv1.Job{
+		TypeMeta:   metav1.TypeMeta{APIVersion: batchv1.SchemeGroupVersion.String(), Kind: "Job"},
+		ObjectMeta: meta,
+		Spec:       jobSpec,
+	}
+
+	log_job.Info("Job 创建成功", "名称", job.Name, "命名空间", namespace, "spec哈希", hash)
+	return job, nil
+}
+
+// JobSpecHash 返回 NewJob 写入 kubeapp.io/spec-hash 注解的哈希
+func JobSpecHash(KubeApp *appsv1alpha1.KubeApp) (string, error) {
+	return specHash(prepareJobSpec(KubeApp))
+}
+
+// NewCronJob 根据 KubeApp 创建 CronJob，生成的 Job 使用 spec.job 的配置
+func NewCronJob(KubeApp *appsv1alpha1.KubeApp, namespace string) (*batchv1.CronJob, error) {
+	if err := validateBatchParams(KubeApp); err != nil {
+		log_job.Error(err, "CronJob 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	cron := KubeApp.Spec.CronJob
+	if cron == nil || cron.Schedule == "" {
+		return nil, fmt.Errorf("CronJob 的 schedule 不能为空")
+	}
+	log_job.Info("开始创建 CronJob", "KubeApp名称", KubeApp.Name, "命名空间", namespace, "schedule", cron.Schedule)
+
+	concurrencyPolicy := cron.ConcurrencyPolicy
+	if concurrencyPolicy == "" {
+		concurrencyPolicy = batchv1.AllowConcurrent
+	}
+
+	cronJob := &batchv1.CronJob{
+		TypeMeta:   metav1.TypeMeta{APIVersion: batchv1.SchemeGroupVersion.String(), Kind: "CronJob"},
+		ObjectMeta: workloadObjectMeta(KubeApp, namespace),
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   cron.Schedule,
+			TimeZone:                   cron.TimeZone,
+			ConcurrencyPolicy:          concurrencyPolicy,
+			Suspend:                    cron.Suspend,
+			StartingDeadlineSeconds:    cron.StartingDeadlineSeconds,
+			SuccessfulJobsHistoryLimit: cron.SuccessfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     cron.FailedJobsHistoryLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{"app": KubeApp.Spec.Deployment.Name},
+				},
+				Spec: prepareJobSpec(KubeApp),
+			},
+		},
+	}
+
+	log_job.Info("CronJob 创建成功", "名称", cronJob.Name, "命名空间", namespace)
+	return cronJob, nil
+}
+
+// prepareJobSpec 在共用 Pod 模板的基础上补充 Job 的重试、超时和清理配置
+func prepareJobSpec(KubeApp *appsv1alpha1.KubeApp) batchv1.JobSpec {
+	template := preparePodTemplate(KubeApp)
+	template.Spec.RestartPolicy = corev1.RestartPolicyOnFailure
+	if job := KubeApp.Spec.Job; job != nil && job.RestartPolicy != "" {
+		template.Spec.RestartPolicy = job.RestartPolicy
+	}
+
+	spec := batchv1.JobSpec{Template: template}
+	if job := KubeApp.Spec.Job; job != nil {
+		spec.BackoffLimit = job.BackoffLimit
+		spec.TTLSecondsAfterFinished = job.TTLSecondsAfterFinished
+		spec.ActiveDeadlineSeconds = job.ActiveDeadlineSeconds
+		spec.Completions = job.Completions
+		spec.Parallelism = job.Parallelism
+	}
+	log_job.V(1).Info("配置 Job", "restartPolicy", spec.Template.Spec.RestartPolicy, "backoffLimit", spec.BackoffLimit)
+	return spec
+}
+
+// specHash 计算 Job spec 的哈希，用于判断已存在的 Job 是否需要重建
+func specHash(spec batchv1.JobSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New32a()
+	h.Write(data)
+	return fmt.Sprintf("%08x", h.Sum32()), nil
+}
+
+// validateBatchParams 校验 Job / CronJob 依赖的 Deployment 规格（容器配置）
+func validateBatchParams(KubeApp *appsv1alpha1.KubeApp) error {
+	if KubeApp == nil {
+		return fmt.Errorf("KubeApp 对象不能为空")
+	}
+	if KubeApp.Spec.Deployment == nil {
+		return fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+	return validateDeploymentSpec(KubeApp.Spec.Deployment)
+}
@@ -2,8 +2,11 @@ package define
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
 	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strings"
 	"time"
@@ -70,4 +73,53 @@ func updateRestartAnnotation(annotations *map[string]string, now string) {
 		*annotations = map[string]string{}
 	}
 	(*annotations)["kubectl.kubernetes.io/restartedAt"] = now
-}
\ No newline at end of file
+}
+
+// ReconcilePaused 判断 KubeApp 是否通过 kubeapp.io/reconcile: paused 冻结了子资源同步
+func ReconcilePaused(KubeApp *appsv1alpha1.KubeApp) bool {
+	return KubeApp.Annotations[appsv1alpha1.ReconcileAnnotation] == appsv1alpha1.PausedValue
+}
+
+// RolloutPaused 判断是否通过 kubeapp.io/rollout: paused 暂停 Deployment 的滚动更新，只对 Deployment 工作负载生效
+func RolloutPaused(KubeApp *appsv1alpha1.KubeApp) bool {
+	return KubeApp.Annotations[appsv1alpha1.RolloutAnnotation] == appsv1alpha1.PausedValue &&
+		KubeApp.Spec.EffectiveWorkloadType() == appsv1alpha1.WorkloadDeployment
+}
+
+// SetKubeAppPaused 设置或移除 KubeApp 的暂停注解（kubeapp.io/reconcile 或 kubeapp.io/rollout），
+// 由 operator 在下一次协调时生效
+func SetKubeAppPaused(namespace, name, annotation string, paused bool) error {
+	if GlobalClient == nil {
+		return fmt.Errorf("k8s client 未初始化，请先调用 Init()")
+	}
+	if annotation != appsv1alpha1.ReconcileAnnotation && annotation != appsv1alpha1.RolloutAnnotation {
+		return fmt.Errorf("不支持的暂停注解: %s", annotation)
+	}
+
+	ctx := context.Background()
+	var kubeapp appsv1alpha1.KubeApp
+	if err := GlobalClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &kubeapp); err != nil {
+		return fmt.Errorf("获取 KubeApp 失败: %v", err)
+	}
+	if annotation == appsv1alpha1.RolloutAnnotation && kubeapp.Spec.EffectiveWorkloadType() != appsv1alpha1.WorkloadDeployment {
+		return fmt.Errorf("只能暂停 Deployment 的滚动更新，当前工作负载为 %s", kubeapp.Spec.EffectiveWorkloadType())
+	}
+
+	// merge patch 中值为 null 表示删除该注解
+	var value interface{}
+	if paused {
+		value = appsv1alpha1.PausedValue
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{annotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if err := GlobalClient.Patch(ctx, &kubeapp, client.RawPatch(types.MergePatchType, patch)); err != nil {
+		return fmt.Errorf("更新 KubeApp 注解失败: %v", err)
+	}
+	return nil
+}
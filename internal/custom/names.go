@@ -0,0 +1,112 @@
+package define
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// FieldManager 是 operator 以 server-side apply 提交子资源时使用的字段管理者名称，
+// 必须保持稳定，否则 managedFields 中会残留旧管理者并产生冲突
+const FieldManager = "kubeapp-operator"
+
+// 子资源名称统一从这里取：优先使用 spec 中的名称，未设置时回退到 KubeApp 名称。
+// 创建、删除、状态汇总和 REST 删除接口都必须使用同一套规则，否则会删错或漏删。
+
+// DeploymentName 返回 KubeApp 对应的 Deployment 名称
+func DeploymentName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.Deployment != nil && kubeApp.Spec.Deployment.Name != "" {
+		return kubeApp.Spec.Deployment.Name
+	}
+	return kubeApp.Name
+}
+
+// ServiceName 返回 KubeApp 对应的 Service 名称
+func ServiceName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.Service != nil && kubeApp.Spec.Service.Name != "" {
+		return kubeApp.Spec.Service.Name
+	}
+	return kubeApp.Name
+}
+
+// IngressName 返回 KubeApp 对应的 Ingress 名称
+func IngressName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.Ingress != nil && kubeApp.Spec.Ingress.Name != "" {
+		return kubeApp.Spec.Ingress.Name
+	}
+	return kubeApp.Name
+}
+
+// HTTPRouteName 返回 KubeApp 对应的 HTTPRoute 名称
+func HTTPRouteName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.Routing != nil && kubeApp.Spec.Routing.Gateway != nil && kubeApp.Spec.Routing.Gateway.Name != "" {
+		return kubeApp.Spec.Routing.Gateway.Name
+	}
+	return kubeApp.Name
+}
+
+// ServiceAccountName 返回 KubeApp 对应的 ServiceAccount 名称，同名的 Role / RoleBinding 也使用它
+func ServiceAccountName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.ServiceAccount != nil && kubeApp.Spec.ServiceAccount.Name != "" {
+		return kubeApp.Spec.ServiceAccount.Name
+	}
+	return kubeApp.Name
+}
+
+// PvcName 返回 KubeApp 对应的 PVC 名称
+func PvcName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.Pvc != nil && kubeApp.Spec.Pvc.Name != "" {
+		return kubeApp.Spec.Pvc.Name
+	}
+	return kubeApp.Name
+}
+
+// PvcSnapshotName 返回 Snapshot 回收策略为该 PVC 创建的 VolumeSnapshot 名称 <PVC名称>-final-<PVC UID 前 8 位>。
+// 名称包含 UID，同名 PVC 重建后不会把之前遗留的快照当作本次的备份
+func PvcSnapshotName(pvc *corev1.PersistentVolumeClaim) string {
+	uid := string(pvc.UID)
+	if len(uid) > 8 {
+		uid = uid[:8]
+	}
+	return fmt.Sprintf("%s-final-%s", pvc.Name, uid)
+}
+
+// childAnnotationExcludedPrefixes 是不复制到子资源上的注解前缀：
+// kubectl.kubernetes.io/last-applied-configuration 含有完整的 KubeApp（包括内联 Secret 的值），
+// kubeapp.io/ 下是 operator 自己的控制注解（暂停、自愈、转换数据），只对 KubeApp 本身有意义
+var childAnnotationExcludedPrefixes = []string{"kubectl.kubernetes.io/", "kubeapp.io/"}
+
+// childAnnotations 返回复制到子资源上的 KubeApp 注解，去掉 kubectl 和 operator 自己使用的注解
+func childAnnotations(kubeApp *appsv1alpha1.KubeApp) map[string]string {
+	var annotations map[string]string
+	for k, v := range kubeApp.Annotations {
+		if childAnnotationExcluded(k) {
+			continue
+		}
+		if annotations == nil {
+			annotations = make(map[string]string, len(kubeApp.Annotations))
+		}
+		annotations[k] = v
+	}
+	return annotations
+}
+
+// childAnnotationExcluded 判断注解是否不能复制到子资源上
+func childAnnotationExcluded(key string) bool {
+	for _, prefix := range childAnnotationExcludedPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HeadlessServiceName 返回 StatefulSet 的 headless 管理 Service 名称
+func HeadlessServiceName(kubeApp *appsv1alpha1.KubeApp) string {
+	if kubeApp.Spec.StatefulSet != nil && kubeApp.Spec.StatefulSet.ServiceName != "" {
+		return kubeApp.Spec.StatefulSet.ServiceName
+	}
+	return DeploymentName(kubeApp) + "-headless"
+}
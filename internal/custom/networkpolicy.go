@@ -0,0 +1,277 @@
+package define
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_np = logf.Log.WithName("networkpolicy-creator")
+
+// DefaultIngressControllerNamespace ingressController 未指定命名空间时使用的默认值
+const DefaultIngressControllerNamespace = "ingress-nginx"
+
+// kube-dns 的位置，egressTo.dns 放行到这些 Pod 的 53 端口
+const (
+	dnsNamespace = "kube-system"
+	dnsPodLabel  = "k8s-app"
+	dnsPodValue  = "kube-dns"
+)
+
+// NetworkPolicyConfigured 判断 KubeApp 是否请求了 NetworkPolicy，策略按 Deployment 的 app 标签选择 Pod
+func NetworkPolicyConfigured(KubeApp *appsv1alpha1.KubeApp) bool {
+	return KubeApp.Spec.NetworkPolicy != nil && KubeApp.Spec.EnableDeployment && KubeApp.Spec.Deployment != nil
+}
+
+// NetworkPolicyKubeAppRefs 返回 ingressFrom 与 egressTo 中引用的其他 KubeApp，未指定命名空间时取本 KubeApp 的命名空间
+func NetworkPolicyKubeAppRefs(KubeApp *appsv1alpha1.KubeApp) []types.NamespacedName {
+	spec := KubeApp.Spec.NetworkPolicy
+	if spec == nil {
+		return nil
+	}
+	var refs []appsv1alpha1.KubeAppRef
+	if spec.IngressFrom != nil {
+		refs = append(refs, spec.IngressFrom.KubeApps...)
+	}
+	if spec.EgressTo != nil {
+		refs = append(refs, spec.EgressTo.KubeApps...)
+	}
+
+	var out []types.NamespacedName
+	for _, ref := range refs {
+		out = append(out, kubeAppRefKey(KubeApp, ref))
+	}
+	return out
+}
+
+// kubeAppRefKey 返回引用的 KubeApp 的 namespace/name
+func kubeAppRefKey(KubeApp *appsv1alpha1.KubeApp, ref appsv1alpha1.KubeAppRef) types.NamespacedName {
+	ns := ref.Namespace
+	if ns == "" {
+		ns = KubeApp.Namespace
+	}
+	return types.NamespacedName{Namespace: ns, Name: ref.Name}
+}
+
+// DeleteNetworkPolicy 删除 KubeApp 对应的 NetworkPolicy，同名但不由该 KubeApp 控制的 NetworkPolicy 会被保留
+func DeleteNetworkPolicy(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	np := &networkingv1.NetworkPolicy{}
+	np.SetName(DeploymentName(KubeApp))
+	np.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, np, KubeApp)
+}
+
+// NewNetworkPolicy 根据 spec.networkPolicy 创建 NetworkPolicy。
+// appLabels 是被引用 KubeApp 的 Pod app 标签（即其 Deployment 名称），由调用方读取；
+// 找不到的引用按 KubeApp 名称作为 app 标签
+func NewNetworkPolicy(KubeApp *appsv1alpha1.KubeApp, namespace string, appLabels map[types.NamespacedName]string) (*networkingv1.NetworkPolicy, error) {
+	if KubeApp == nil || KubeApp.Spec.Deployment == nil {
+		return nil, fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+	spec := KubeApp.Spec.NetworkPolicy
+	if err := validateNetworkPolicySpec(spec); err != nil {
+		log_np.Error(err, "NetworkPolicy 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	log_np.Info("开始创建 NetworkPolicy", "KubeApp名称", KubeApp.Name, "命名空间", namespace)
+
+	appPeer := func(ref appsv1alpha1.KubeAppRef) networkingv1.NetworkPolicyPeer {
+		key := kubeAppRefKey(KubeApp, ref)
+		app, ok := appLabels[key]
+		if !ok {
+			app = ref.Name
+		}
+		peer := networkingv1.NetworkPolicyPeer{
+			PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": app}},
+		}
+		if key.Namespace != namespace {
+			peer.NamespaceSelector = namespaceSelector(key.Namespace)
+		}
+		return peer
+	}
+
+	np := &networkingv1.NetworkPolicy{
+		TypeMeta: metav1.TypeMeta{APIVersion: networkingv1.SchemeGroupVersion.String(), Kind: "NetworkPolicy"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        DeploymentName(KubeApp),
+			Namespace:   namespace,
+			Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+			Annotations: childAnnotations(KubeApp),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": KubeApp.Spec.Deployment.Name,
+				},
+			},
+		},
+	}
+
+	// policyTypes 含 Ingress 而没有入站规则时拒绝所有入站流量，Egress 同理；
+	// 只设置了 egressTo 时不限制入站流量
+	if spec.IngressFrom != nil || spec.EgressTo == nil {
+		np.Spec.PolicyTypes = []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}
+	}
+
+	if in := spec.IngressFrom; in != nil {
+		var from []networkingv1.NetworkPolicyPeer
+		for _, ns := range in.Namespaces {
+			from = append(from, networkingv1.NetworkPolicyPeer{NamespaceSelector: namespaceSelector(ns)})
+		}
+		for _, ref := range in.KubeApps {
+			from = append(from, appPeer(ref))
+		}
+		if ic := in.IngressController; ic != nil {
+			peer := networkingv1.NetworkPolicyPeer{NamespaceSelector: namespaceSelector(ingressControllerNamespace(ic))}
+			if len(ic.PodLabels) > 0 {
+				peer.PodSelector = &metav1.LabelSelector{MatchLabels: ic.PodLabels}
+			}
+			from = append(from, peer)
+		}
+		if len(from) > 0 {
+			np.Spec.Ingress = []networkingv1.NetworkPolicyIngressRule{{From: from}}
+		}
+	}
+
+	if eg := spec.EgressTo; eg != nil {
+		np.Spec.PolicyTypes = append(np.Spec.PolicyTypes, networkingv1.PolicyTypeEgress)
+
+		var to []networkingv1.NetworkPolicyPeer
+		for _, ref := range eg.KubeApps {
+			to = append(to, appPeer(ref))
+		}
+		for i := range eg.CIDRs {
+			to = append(to, networkingv1.NetworkPolicyPeer{IPBlock: eg.CIDRs[i].DeepCopy()})
+		}
+		if len(to) > 0 {
+			np.Spec.Egress = append(np.Spec.Egress, networkingv1.NetworkPolicyEgressRule{To: to})
+		}
+		if eg.DNS {
+			np.Spec.Egress = append(np.Spec.Egress, dnsEgressRule())
+		}
+	}
+
+	log_np.Info("NetworkPolicy 创建成功", "名称", np.Name, "命名空间", namespace,
+		"入站规则", len(np.Spec.Ingress), "出站规则", len(np.Spec.Egress))
+	return np, nil
+}
+
+// namespaceSelector 通过 kubernetes.io/metadata.name 标签选择命名空间
+func namespaceSelector(namespace string) *metav1.LabelSelector {
+	return &metav1.LabelSelector{MatchLabels: map[string]string{corev1.LabelMetadataName: namespace}}
+}
+
+// ingressControllerNamespace 返回 Ingress Controller 所在命名空间，默认 ingress-nginx
+func ingressControllerNamespace(ic *appsv1alpha1.IngressControllerRef) string {
+	if ic.Namespace == "" {
+		return DefaultIngressControllerNamespace
+	}
+	return ic.Namespace
+}
+
+// dnsEgressRule 放行到 kube-system 中 kube-dns 的 UDP/TCP 53 端口
+func dnsEgressRule() networkingv1.NetworkPolicyEgressRule {
+	udp, tcp := corev1.ProtocolUDP, corev1.ProtocolTCP
+	port := intstr.FromInt32(53)
+	return networkingv1.NetworkPolicyEgressRule{
+		To: []networkingv1.NetworkPolicyPeer{{
+			NamespaceSelector: namespaceSelector(dnsNamespace),
+			PodSelector:       &metav1.LabelSelector{MatchLabels: map[string]string{dnsPodLabel: dnsPodValue}},
+		}},
+		Ports: []networkingv1.NetworkPolicyPort{
+			{Protocol: &udp, Port: &port},
+			{Protocol: &tcp, Port: &port},
+		},
+	}
+}
+
+// validateNetworkPolicySpec 校验命名空间、KubeApp 引用和 CIDR
+func validateNetworkPolicySpec(spec *appsv1alpha1.NetworkPolicySpec) error {
+	if spec == nil {
+		return fmt.Errorf("KubeApp 未配置 networkPolicy")
+	}
+
+	validateRefs := func(refs []appsv1alpha1.KubeAppRef) error {
+		for _, ref := range refs {
+			if errs := validation.IsDNS1123Subdomain(ref.Name); len(errs) > 0 {
+				return fmt.Errorf("KubeApp 名称 %q 不合法: %s", ref.Name, strings.Join(errs, ", "))
+			}
+			if err := validateNamespaceName(ref.Namespace, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if in := spec.IngressFrom; in != nil {
+		for _, ns := range in.Namespaces {
+			if err := validateNamespaceName(ns, false); err != nil {
+				return err
+			}
+		}
+		if err := validateRefs(in.KubeApps); err != nil {
+			return err
+		}
+		if ic := in.IngressController; ic != nil {
+			if err := validateNamespaceName(ic.Namespace, true); err != nil {
+				return err
+			}
+			for k, v := range ic.PodLabels {
+				if errs := validation.IsQualifiedName(k); len(errs) > 0 {
+					return fmt.Errorf("ingressController 标签 %q 不合法: %s", k, strings.Join(errs, ", "))
+				}
+				if errs := validation.IsValidLabelValue(v); len(errs) > 0 {
+					return fmt.Errorf("ingressController 标签 %s 的值 %q 不合法: %s", k, v, strings.Join(errs, ", "))
+				}
+			}
+		}
+	}
+
+	if eg := spec.EgressTo; eg != nil {
+		if err := validateRefs(eg.KubeApps); err != nil {
+			return err
+		}
+		for _, block := range eg.CIDRs {
+			_, ipNet, err := net.ParseCIDR(block.CIDR)
+			if err != nil {
+				return fmt.Errorf("CIDR %q 不合法: %v", block.CIDR, err)
+			}
+			for _, except := range block.Except {
+				exceptIP, exceptNet, err := net.ParseCIDR(except)
+				if err != nil {
+					return fmt.Errorf("CIDR %s 的 except %q 不合法: %v", block.CIDR, except, err)
+				}
+				exceptOnes, _ := exceptNet.Mask.Size()
+				ones, _ := ipNet.Mask.Size()
+				if !ipNet.Contains(exceptIP) || exceptOnes <= ones {
+					return fmt.Errorf("except %s 必须是 CIDR %s 的子网", except, block.CIDR)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// validateNamespaceName 校验命名空间名称，optional 为 true 时允许为空
+func validateNamespaceName(ns string, optional bool) error {
+	if ns == "" && optional {
+		return nil
+	}
+	if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+		return fmt.Errorf("命名空间 %q 不合法: %s", ns, strings.Join(errs, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package define
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_pdb = logf.Log.WithName("pdb-creator")
+
+// DisruptionBudgetConfigured 判断 KubeApp 是否请求了 PDB。
+// 只有 Deployment 和 StatefulSet 支持，其他类型的 disruptionBudget 在准入阶段被拒绝
+func DisruptionBudgetConfigured(KubeApp *appsv1alpha1.KubeApp) bool {
+	if !KubeApp.Spec.EnableDeployment || KubeApp.Spec.Deployment == nil || KubeApp.Spec.DisruptionBudget == nil {
+		return false
+	}
+	switch KubeApp.Spec.EffectiveWorkloadType() {
+	case appsv1alpha1.WorkloadDeployment, appsv1alpha1.WorkloadStatefulSet:
+		return true
+	}
+	return false
+}
+
+// DisruptionBudgetReplicas 返回判断是否创建 PDB 时使用的副本数：启用 autoscaling 时取 minReplicas。
+// 只有 1 个副本时 PDB 会让节点驱逐永远无法完成，operator 不创建 PDB 而是在 status 中提示
+func DisruptionBudgetReplicas(KubeApp *appsv1alpha1.KubeApp) int32 {
+	if AutoscalingEnabled(KubeApp) {
+		return autoscalingMinReplicas(KubeApp)
+	}
+	return prepareReplicas(KubeApp)
+}
+
+// DeletePodDisruptionBudget 删除 KubeApp 对应的 PDB，同名但不由该 KubeApp 控制的 PDB 会被保留
+func DeletePodDisruptionBudget(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	pdb := &policyv1.PodDisruptionBudget{}
+	pdb.SetName(DeploymentName(KubeApp))
+	pdb.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, pdb, KubeApp)
+}
+
+// NewPodDisruptionBudget 根据 spec.disruptionBudget 创建 policy/v1 PDB，选择器与 NewDeployment 的 app 标签一致
+func NewPodDisruptionBudget(KubeApp *appsv1alpha1.KubeApp, namespace string) (*policyv1.PodDisruptionBudget, error) {
+	if KubeApp == nil || KubeApp.Spec.Deployment == nil {
+		return nil, fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+	spec := KubeApp.Spec.DisruptionBudget
+	if err := validateDisruptionBudgetSpec(spec); err != nil {
+		log_pdb.Error(err, "PDB 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+	log_pdb.Info("开始创建 PDB", "KubeApp名称", KubeApp.Name, "命名空间", namespace)
+
+	pdb := &policyv1.PodDisruptionBudget{
+		TypeMeta: metav1.TypeMeta{APIVersion: policyv1.SchemeGroupVersion.String(), Kind: "PodDisruptionBudget"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        DeploymentName(KubeApp),
+			Namespace:   namespace,
+			Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+			Annotations: childAnnotations(KubeApp),
+		},
+		Spec: policyv1.PodDisruptionBudgetSpec{
+			MinAvailable:   spec.MinAvailable,
+			MaxUnavailable: spec.MaxUnavailable,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": KubeApp.Spec.Deployment.Name,
+				},
+			},
+		},
+	}
+
+	log_pdb.Info("PDB 创建成功", "名称", pdb.Name, "命名空间", namespace,
+		"minAvailable", spec.MinAvailable, "maxUnavailable", spec.MaxUnavailable)
+	return pdb, nil
+}
+
+// validateDisruptionBudgetSpec 校验 minAvailable 与 maxUnavailable 必须且只能设置一个，取值为非负整数或百分比
+func validateDisruptionBudgetSpec(spec *appsv1alpha1.DisruptionBudgetSpec) error {
+	if spec == nil {
+		return fmt.Errorf("KubeApp 未配置 disruptionBudget")
+	}
+	switch {
+	case spec.MinAvailable != nil && spec.MaxUnavailable != nil:
+		return fmt.Errorf("minAvailable 和 maxUnavailable 只能设置一个")
+	case spec.MinAvailable != nil:
+		return validateIntOrPercent("minAvailable", *spec.MinAvailable, true)
+	case spec.MaxUnavailable != nil:
+		return validateIntOrPercent("maxUnavailable", *spec.MaxUnavailable, true)
+	}
+	return fmt.Errorf("minAvailable 和 maxUnavailable 必须设置一个")
+}
@@ -17,6 +17,8 @@ type PodContainer struct {
 	Ready        bool     `json:"ready"`
 	State        string   `json:"state"`
 	RestartCount int32    `json:"restart_count"`
+	// Sidecar 表示该 init 容器是原生 sidecar（restartPolicy: Always），会与主容器一起持续运行
+	Sidecar bool `json:"sidecar"`
 }
 
 type PodInfo struct {
@@ -82,65 +84,39 @@ func ListPods(namespace string) ([]PodInfo, error) {
 		var errorReasons []string
 
 		for _, c := range pod.Spec.Containers {
-			cName := c.Name
-			containerNames = append(containerNames, cName)
-			img := c.Image
-			images = append(images, img)
-
-			var containerPorts []string
+			containerNames = append(containerNames, c.Name)
+			images = append(images, c.Image)
 			for _, p := range c.Ports {
-				containerPorts = append(containerPorts, strconv.Itoa(int(p.ContainerPort)))
 				ports = append(ports, strconv.Itoa(int(p.ContainerPort)))
 			}
 
-			ready := false
-			state := "Unknown"
-			restartCount := int32(0)
-
-			for _, cs := range pod.Status.ContainerStatuses {
-				if cs.Name != c.Name {
-					continue
-				}
-				restartCount = cs.RestartCount
-				restarts += restartCount
-				if cs.Ready {
-					ready = true
-					readyCount++
-				}
-				if cs.State.Waiting != nil {
-					state = cs.State.Waiting.Reason
-					if cs.State.Waiting.Message != "" {
-						errorReasons = append(errorReasons, cs.State.Waiting.Message)
-					}
-				} else if cs.State.Terminated != nil {
-					state = cs.State.Terminated.Reason
-					if cs.State.Terminated.Message != "" {
-						errorReasons = append(errorReasons, cs.State.Terminated.Message)
-					}
-				} else if cs.State.Running != nil {
-					state = "Running"
-				}
+			container, reasons := podContainerStatus(c, pod.Status.ContainerStatuses)
+			if container.Ready {
+				readyCount++
 			}
-
-			containers = append(containers, PodContainer{
-				Name:         cName,
-				Image:        img,
-				Ports:        containerPorts,
-				Ready:        ready,
-				State:        state,
-				RestartCount: restartCount,
-			})
+			restarts += container.RestartCount
+			errorReasons = append(errorReasons, reasons...)
+			containers = append(containers, container)
 		}
 
-		// InitContainers
+		// InitContainers：原生 sidecar 与主容器一样计入就绪数，和 kubectl 的 READY 列一致
 		var initContainers []PodContainer
 		var initNames []string
+		totalCount := len(pod.Spec.Containers)
 		for _, c := range pod.Spec.InitContainers {
 			initNames = append(initNames, c.Name)
-			initContainers = append(initContainers, PodContainer{
-				Name:  c.Name,
-				Image: c.Image,
-			})
+
+			container, reasons := podContainerStatus(c, pod.Status.InitContainerStatuses)
+			container.Sidecar = c.RestartPolicy != nil && *c.RestartPolicy == corev1.ContainerRestartPolicyAlways
+			if container.Sidecar {
+				totalCount++
+				if container.Ready {
+					readyCount++
+				}
+			}
+			restarts += container.RestartCount
+			errorReasons = append(errorReasons, reasons...)
+			initContainers = append(initContainers, container)
 		}
 
 		podInfo := PodInfo{
@@ -151,7 +127,7 @@ func ListPods(namespace string) ([]PodInfo, error) {
 			Ports:          ports,
 			QoS:            qos,
 			PodStatus:       string(pod.Status.Phase),
-			ReadyCount:     fmt.Sprintf("%d/%d", readyCount, len(pod.Spec.Containers)),
+			ReadyCount:     fmt.Sprintf("%d/%d", readyCount, totalCount),
 			Restarts:       restarts,
 			PodErrorReason: errorReasons,
 			StartTime:      utils.TimeToCST(cstStart),
@@ -169,6 +145,41 @@ func ListPods(namespace string) ([]PodInfo, error) {
 	return result, nil
 }
 
+// podContainerStatus 根据容器状态汇总单个容器的就绪、状态和重启次数，并返回等待/终止时的错误信息
+func podContainerStatus(c corev1.Container, statuses []corev1.ContainerStatus) (PodContainer, []string) {
+	container := PodContainer{
+		Name:  c.Name,
+		Image: c.Image,
+		State: "Unknown",
+	}
+	for _, p := range c.Ports {
+		container.Ports = append(container.Ports, strconv.Itoa(int(p.ContainerPort)))
+	}
+
+	var errorReasons []string
+	for _, cs := range statuses {
+		if cs.Name != c.Name {
+			continue
+		}
+		container.RestartCount = cs.RestartCount
+		container.Ready = cs.Ready
+		if cs.State.Waiting != nil {
+			container.State = cs.State.Waiting.Reason
+			if cs.State.Waiting.Message != "" {
+				errorReasons = append(errorReasons, cs.State.Waiting.Message)
+			}
+		} else if cs.State.Terminated != nil {
+			container.State = cs.State.Terminated.Reason
+			if cs.State.Terminated.Message != "" {
+				errorReasons = append(errorReasons, cs.State.Terminated.Message)
+			}
+		} else if cs.State.Running != nil {
+			container.State = "Running"
+		}
+	}
+	return container, errorReasons
+}
+
 // RestartPod 删除指定的 Pod，K8S 控制器会自动拉起新的 Pod，相当于重启
 func RestartPod(namespace, podName string) error {
 	if GlobalClient == nil {
@@ -0,0 +1,157 @@
+package define
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PodSecurityEnforceLabel 命名空间上 Pod Security Admission 强制执行级别的标签
+const PodSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+// Pod Security Standards 的级别
+const (
+	PodSecurityPrivileged = "privileged"
+	PodSecurityBaseline   = "baseline"
+	PodSecurityRestricted = "restricted"
+)
+
+// baselineCapabilities baseline 级别允许额外添加的 capability
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true, "KILL": true, "MKNOD": true,
+	"NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+// PodSecurityViolations 检查 KubeApp 生成的 Pod 模板是否满足 baseline / restricted 级别，返回违反的规则。
+// 只覆盖 KubeApp 能够生成的字段（hostPath、hostPort、securityContext 等），不是完整的 PSA 实现
+func PodSecurityViolations(KubeApp *appsv1alpha1.KubeApp, level string) []string {
+	if KubeApp.Spec.Deployment == nil || (level != PodSecurityBaseline && level != PodSecurityRestricted) {
+		return nil
+	}
+	pod := preparePodTemplate(KubeApp).Spec
+	restricted := level == PodSecurityRestricted
+
+	var violations []string
+	add := func(format string, args ...interface{}) {
+		violations = append(violations, fmt.Sprintf(format, args...))
+	}
+
+	if pod.HostNetwork || pod.HostPID || pod.HostIPC {
+		add("host namespaces are not allowed")
+	}
+	for _, v := range pod.Volumes {
+		if v.HostPath != nil {
+			add("volume %q uses hostPath", v.Name)
+		} else if restricted && !restrictedVolumeType(v) {
+			add("volume %q uses a volume type that is not allowed", v.Name)
+		}
+	}
+
+	podSC := pod.SecurityContext
+	if podSC == nil {
+		podSC = &corev1.PodSecurityContext{}
+	}
+	if seccompUnconfined(podSC.SeccompProfile) {
+		add("pod seccompProfile must not be Unconfined")
+	}
+	if restricted && podSC.RunAsUser != nil && *podSC.RunAsUser == 0 {
+		add("pod must not set runAsUser=0")
+	}
+
+	containers := append(append([]corev1.Container{}, pod.InitContainers...), pod.Containers...)
+	for _, c := range containers {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &corev1.SecurityContext{}
+		}
+		for _, p := range c.Ports {
+			if p.HostPort != 0 {
+				add("container %q must not use hostPort %d", c.Name, p.HostPort)
+			}
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			add("container %q must not be privileged", c.Name)
+		}
+		if seccompUnconfined(sc.SeccompProfile) {
+			add("container %q seccompProfile must not be Unconfined", c.Name)
+		}
+		var added []corev1.Capability
+		if sc.Capabilities != nil {
+			added = sc.Capabilities.Add
+		}
+		for _, capability := range added {
+			if !baselineCapabilities[capability] || (restricted && capability != "NET_BIND_SERVICE") {
+				add("container %q must not add capability %s", c.Name, capability)
+			}
+		}
+		if !restricted {
+			continue
+		}
+
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add("container %q must set allowPrivilegeEscalation=false", c.Name)
+		}
+		if !dropsAllCapabilities(sc.Capabilities) {
+			add("container %q must drop ALL capabilities", c.Name)
+		}
+		if nonRoot := sc.RunAsNonRoot; (nonRoot != nil && !*nonRoot) || (nonRoot == nil && (podSC.RunAsNonRoot == nil || !*podSC.RunAsNonRoot)) {
+			add("container %q must set runAsNonRoot=true", c.Name)
+		}
+		if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+			add("container %q must not set runAsUser=0", c.Name)
+		}
+		if sc.SeccompProfile == nil && podSC.SeccompProfile == nil {
+			add("container %q must set seccompProfile to RuntimeDefault or Localhost", c.Name)
+		}
+	}
+	return violations
+}
+
+// seccompUnconfined 判断 seccompProfile 是否为 Unconfined
+func seccompUnconfined(profile *corev1.SeccompProfile) bool {
+	return profile != nil && profile.Type == corev1.SeccompProfileTypeUnconfined
+}
+
+// dropsAllCapabilities 判断是否丢弃了全部 capability
+func dropsAllCapabilities(caps *corev1.Capabilities) bool {
+	if caps == nil {
+		return false
+	}
+	for _, c := range caps.Drop {
+		if c == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// restrictedVolumeType 判断卷类型是否为 restricted 级别允许的类型
+func restrictedVolumeType(v corev1.Volume) bool {
+	src := v.VolumeSource
+	return src.ConfigMap != nil || src.Secret != nil || src.EmptyDir != nil || src.PersistentVolumeClaim != nil ||
+		src.Projected != nil || src.DownwardAPI != nil || src.CSI != nil || src.Ephemeral != nil
+}
+
+// validateSecurityProfile 校验 securityProfile：custom 至少配置一个安全上下文，其他级别不接受自定义安全上下文，
+// 并且选择 restricted / baseline 时生成的 Pod 必须满足该级别（例如不能挂载 hostPath）
+func validateSecurityProfile(KubeApp *appsv1alpha1.KubeApp) error {
+	profile := KubeApp.Spec.Deployment.SecurityProfile
+	if profile == nil {
+		return nil
+	}
+	if profile.Type == appsv1alpha1.SecurityProfileCustom {
+		if profile.PodSecurityContext == nil && profile.ContainerSecurityContext == nil {
+			return fmt.Errorf("custom 安全配置必须设置 podSecurityContext 或 containerSecurityContext")
+		}
+		return nil
+	}
+	if profile.PodSecurityContext != nil || profile.ContainerSecurityContext != nil {
+		return fmt.Errorf("podSecurityContext / containerSecurityContext 只能用于 custom 安全配置，当前为 %s", profile.Type)
+	}
+	if violations := PodSecurityViolations(KubeApp, string(profile.Type)); len(violations) > 0 {
+		return fmt.Errorf("Pod 不满足 %s 级别: %s", profile.Type, strings.Join(violations, "; "))
+	}
+	return nil
+}
@@ -38,6 +38,13 @@ type PVCInfo struct {
 // 创建PVC日志记录器
 var log_pvc = logf.Log.WithName("pvc-creator")
 
+// VolumeSnapshotGVK external-snapshotter 提供的 VolumeSnapshot 类型
+var VolumeSnapshotGVK = schema.GroupVersionKind{
+    Group:   "snapshot.storage.k8s.io",
+    Version: "v1",
+    Kind:    "VolumeSnapshot",
+}
+
 
 // NewPvc 创建 PVC 对象结构
 
@@ -60,8 +67,12 @@ func NewPvc(ctx context.Context, KubeApp *appsv1alpha1.KubeApp, namespace string
 
     pvc.SetName(pvcSpec.Name)
     pvc.SetNamespace(namespace)
-    pvc.SetLabels(utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}))
-    pvc.SetAnnotations(KubeApp.Annotations)
+    // PVC 不设置 ownerReference（避免随 KubeApp 被级联回收），通过标签关联回 KubeApp
+    pvc.SetLabels(utils.MergeMaps(KubeApp.Labels, map[string]string{
+        "managed-by":           "KubeApp-operator",
+        appsv1alpha1.NameLabel: KubeApp.Name,
+    }))
+    pvc.SetAnnotations(childAnnotations(KubeApp))
 
     accessModes := make([]interface{}, len(pvcSpec.AccessModes))
     for i, mode := range pvcSpec.AccessModes {
@@ -95,7 +106,7 @@ func ApplyPvc(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.Kube
     // 使用 Server-Side Apply
     err = cli.Patch(ctx, pvc, client.Apply, &client.PatchOptions{
         Force:        pointer.Bool(true),
-        FieldManager: "KubeApp-operator",
+        FieldManager: FieldManager,
     })
 
     if err != nil {
@@ -107,15 +118,33 @@ func ApplyPvc(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.Kube
     return nil
 }
 
-// DeletePvc 删除 PVC
-func DeletePvc(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
-    name := KubeApp.Spec.Pvc.Name
-    if name == "" {
-        name = KubeApp.Name
+// NewPvcSnapshot 构建 Snapshot 回收策略为 pvc 创建的 VolumeSnapshot（不设置 ownerReference，KubeApp 删除后保留）
+func NewPvcSnapshot(KubeApp *appsv1alpha1.KubeApp, pvc *corev1.PersistentVolumeClaim) *unstructured.Unstructured {
+    snap := &unstructured.Unstructured{}
+    snap.SetGroupVersionKind(VolumeSnapshotGVK)
+    snap.SetName(PvcSnapshotName(pvc))
+    snap.SetNamespace(pvc.Namespace)
+    snap.SetLabels(map[string]string{
+        "managed-by":           "KubeApp-operator",
+        appsv1alpha1.NameLabel: KubeApp.Name,
+    })
+
+    spec := map[string]interface{}{
+        "source": map[string]interface{}{
+            "persistentVolumeClaimName": pvc.Name,
+        },
+    }
+    if KubeApp.Spec.Pvc != nil && KubeApp.Spec.Pvc.SnapshotClassName != nil && *KubeApp.Spec.Pvc.SnapshotClassName != "" {
+        spec["volumeSnapshotClassName"] = *KubeApp.Spec.Pvc.SnapshotClassName
     }
+    snap.Object["spec"] = spec
+    return snap
+}
 
+// DeletePvc 删除 PVC
+func DeletePvc(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
     pvc := &corev1.PersistentVolumeClaim{}
-    pvc.SetName(name)
+    pvc.SetName(PvcName(KubeApp))
     pvc.SetNamespace(namespace)
 
     return utils.DeleteIfExists(ctx, cli, pvc)
@@ -0,0 +1,363 @@
+package define
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_rev = logf.Log.WithName("revision-creator")
+
+// RevisionHistoryLimit 每个 KubeApp 保留的 ControllerRevision 数量，超出时删除编号最小的
+const RevisionHistoryLimit = 10
+
+// RevisionHashLabel ControllerRevision 上记录 spec 哈希的标签
+const RevisionHashLabel = "kubeapp.io/revision-hash"
+
+// RedactedSecretValue 替换 ControllerRevision 中 spec.secrets 的 stringData 值和文件内容，Secret 的明文不落入版本历史
+const RedactedSecretValue = "<redacted>"
+
+// RevisionInfo 是一个 spec 版本及其相对上一版本的变化，供 REST 接口返回
+type RevisionInfo struct {
+	Revision  int64        `json:"revision"`
+	Name      string       `json:"name"`
+	Hash      string       `json:"hash"`
+	Image     string       `json:"image"`
+	Replicas  int32        `json:"replicas"`
+	Current   bool         `json:"current"`
+	CreatedAt string       `json:"created_at"`
+	Changes   []SpecChange `json:"changes"`
+}
+
+// SpecChange 是两个版本之间一个字段的变化，Path 形如 deployment.image、deployment.env[0].value
+type SpecChange struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old,omitempty"`
+	New  interface{} `json:"new,omitempty"`
+}
+
+// SpecHash 计算 KubeApp spec 的哈希，同样的 spec 对应同一个 ControllerRevision。
+// Secret 的值不参与计算，只修改 Secret 的值不会产生新版本。
+// 不同的 spec 也可能得到相同的哈希，判断是否为同一版本要用 RevisionMatches
+func SpecHash(KubeApp *appsv1alpha1.KubeApp) (string, error) {
+	data, err := json.Marshal(redactSecrets(&KubeApp.Spec))
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New32a()
+	h.Write(data)
+	return fmt.Sprintf("%08x", h.Sum32()), nil
+}
+
+// ListRevisions 列出由 KubeApp 控制的 ControllerRevision，按编号升序。
+// 只带有同名 kubeapp.io/name 标签的版本（例如同名 KubeApp 删除重建前留下的）不属于该 KubeApp，不参与匹配和清理
+func ListRevisions(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp) ([]appsv1.ControllerRevision, error) {
+	var list appsv1.ControllerRevisionList
+	if err := cli.List(ctx, &list, client.InNamespace(KubeApp.Namespace), client.MatchingLabels{appsv1alpha1.NameLabel: KubeApp.Name}); err != nil {
+		return nil, err
+	}
+	var revisions []appsv1.ControllerRevision
+	for i := range list.Items {
+		if metav1.IsControlledBy(&list.Items[i], KubeApp) {
+			revisions = append(revisions, list.Items[i])
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool { return revisions[i].Revision < revisions[j].Revision })
+	return revisions, nil
+}
+
+// RevisionName 返回 ControllerRevision 的名称 <KubeApp名称>-<spec哈希>。
+// 32 位哈希可能与另一个 spec 冲突，此时与 Deployment 控制器的 collisionCount 一样追加冲突计数：<KubeApp名称>-<spec哈希>-<冲突计数>
+func RevisionName(KubeApp *appsv1alpha1.KubeApp, hash string, collisionCount int32) string {
+	if collisionCount == 0 {
+		return fmt.Sprintf("%s-%s", KubeApp.Name, hash)
+	}
+	return fmt.Sprintf("%s-%s-%d", KubeApp.Name, hash, collisionCount)
+}
+
+// RevisionMatches 判断 ControllerRevision 记录的是否就是 KubeApp 当前的 spec。
+// 哈希相同只说明可能相同，还要比较记录的 spec 内容
+func RevisionMatches(rev *appsv1.ControllerRevision, KubeApp *appsv1alpha1.KubeApp) (bool, error) {
+	hash, err := SpecHash(KubeApp)
+	if err != nil {
+		return false, err
+	}
+	if rev.Labels[RevisionHashLabel] != hash {
+		return false, nil
+	}
+	spec, err := RevisionSpec(rev)
+	if err != nil {
+		return false, err
+	}
+	recorded, err := json.Marshal(spec)
+	if err != nil {
+		return false, err
+	}
+	current, err := json.Marshal(redactSecrets(&KubeApp.Spec))
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(recorded, current), nil
+}
+
+// FindRevision 返回记录 KubeApp 当前 spec 的 ControllerRevision 在 revisions 中的下标，不存在时返回 -1
+func FindRevision(revisions []appsv1.ControllerRevision, KubeApp *appsv1alpha1.KubeApp) (int, error) {
+	for i := range revisions {
+		matched, err := RevisionMatches(&revisions[i], KubeApp)
+		if err != nil {
+			return -1, err
+		}
+		if matched {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// NewRevision 把 KubeApp 当前的 spec 记录为编号 revision 的 ControllerRevision，名称见 RevisionName。
+// spec.secrets 的值替换为 RedactedSecretValue
+func NewRevision(KubeApp *appsv1alpha1.KubeApp, revision int64, collisionCount int32) (*appsv1.ControllerRevision, error) {
+	hash, err := SpecHash(KubeApp)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"apiVersion": appsv1alpha1.GroupVersion.String(),
+		"kind":       "KubeApp",
+		"spec":       redactSecrets(&KubeApp.Spec),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &appsv1.ControllerRevision{
+		TypeMeta: metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "ControllerRevision"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RevisionName(KubeApp, hash, collisionCount),
+			Namespace: KubeApp.Namespace,
+			Labels: utils.MergeMaps(KubeApp.Labels, map[string]string{
+				"managed-by":           "KubeApp-operator",
+				appsv1alpha1.NameLabel: KubeApp.Name,
+				RevisionHashLabel:      hash,
+			}),
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: revision,
+	}, nil
+}
+
+// RevisionSpec 从 ControllerRevision 中解析出记录的 KubeApp spec
+func RevisionSpec(rev *appsv1.ControllerRevision) (*appsv1alpha1.KubeAppSpec, error) {
+	var snapshot struct {
+		Spec appsv1alpha1.KubeAppSpec `json:"spec"`
+	}
+	if err := json.Unmarshal(rev.Data.Raw, &snapshot); err != nil {
+		return nil, fmt.Errorf("解析 ControllerRevision %s 失败: %v", rev.Name, err)
+	}
+	return &snapshot.Spec, nil
+}
+
+// DescribeRevisions 把 ControllerRevision 转换为 RevisionInfo，并计算每个版本相对上一版本的变化
+func DescribeRevisions(revisions []appsv1.ControllerRevision, currentRevision int64) ([]RevisionInfo, error) {
+	var infos []RevisionInfo
+	var previous map[string]interface{}
+	for i := range revisions {
+		rev := &revisions[i]
+		spec, err := RevisionSpec(rev)
+		if err != nil {
+			return nil, err
+		}
+		// 早期版本记录了 Secret 明文，返回前同样脱敏
+		current, err := specFields(redactSecrets(spec))
+		if err != nil {
+			return nil, err
+		}
+
+		info := RevisionInfo{
+			Revision:  rev.Revision,
+			Name:      rev.Name,
+			Hash:      rev.Labels[RevisionHashLabel],
+			Current:   rev.Revision == currentRevision,
+			CreatedAt: rev.CreationTimestamp.Format("2006-01-02 15:04:05"),
+			Changes:   DiffSpecs(previous, current),
+		}
+		if spec.Deployment != nil {
+			info.Image = spec.Deployment.Image
+			if spec.Deployment.Replicas != nil {
+				info.Replicas = *spec.Deployment.Replicas
+			}
+		}
+		infos = append(infos, info)
+		previous = current
+	}
+	return infos, nil
+}
+
+// RollbackKubeApp 把 KubeApp 的 spec 恢复为编号 revision 的 ControllerRevision 中记录的内容，
+// operator 下发后该版本会成为编号最大的版本。版本中没有 Secret 的值，沿用 KubeApp 当前 spec 中的值
+func RollbackKubeApp(ctx context.Context, cli client.Client, namespace, name string, revision int64) error {
+	var kubeapp appsv1alpha1.KubeApp
+	if err := cli.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &kubeapp); err != nil {
+		return fmt.Errorf("获取 KubeApp 失败: %v", err)
+	}
+	revisions, err := ListRevisions(ctx, cli, &kubeapp)
+	if err != nil {
+		return fmt.Errorf("获取 KubeApp 版本失败: %v", err)
+	}
+	for i := range revisions {
+		if revisions[i].Revision != revision {
+			continue
+		}
+		spec, err := RevisionSpec(&revisions[i])
+		if err != nil {
+			return err
+		}
+		if err := restoreSecrets(spec, &kubeapp.Spec); err != nil {
+			return fmt.Errorf("无法回滚到版本 %d: %v", revision, err)
+		}
+		kubeapp.Spec = *spec
+		if err := cli.Update(ctx, &kubeapp); err != nil {
+			return fmt.Errorf("回滚 KubeApp 失败: %v", err)
+		}
+		log_rev.Info("KubeApp 已回滚", "KubeApp名称", name, "命名空间", namespace, "版本", revision)
+		return nil
+	}
+	return fmt.Errorf("KubeApp %s/%s 不存在版本 %d", namespace, name, revision)
+}
+
+// redactSecrets 返回 spec 的副本，spec.secrets 的 stringData 值和文件内容替换为 RedactedSecretValue，键和文件名保留
+func redactSecrets(spec *appsv1alpha1.KubeAppSpec) *appsv1alpha1.KubeAppSpec {
+	out := spec.DeepCopy()
+	for i := range out.Secrets {
+		for k := range out.Secrets[i].StringData {
+			out.Secrets[i].StringData[k] = RedactedSecretValue
+		}
+		for j := range out.Secrets[i].Files {
+			out.Secrets[i].Files[j].Content = RedactedSecretValue
+		}
+	}
+	return out
+}
+
+// restoreSecrets 把 spec 中被脱敏的 Secret 值替换为 current 中同名 Secret 的同名键或文件的值，
+// current 中已不存在对应的键时返回错误
+func restoreSecrets(spec, current *appsv1alpha1.KubeAppSpec) error {
+	values := map[string]map[string]string{}
+	files := map[string]map[string]string{}
+	for _, secret := range current.Secrets {
+		values[secret.Name] = secret.StringData
+		files[secret.Name] = map[string]string{}
+		for _, f := range secret.Files {
+			files[secret.Name][f.Name] = f.Content
+		}
+	}
+	for i := range spec.Secrets {
+		secret := &spec.Secrets[i]
+		for k, v := range secret.StringData {
+			if v != RedactedSecretValue {
+				continue
+			}
+			value, ok := values[secret.Name][k]
+			if !ok {
+				return fmt.Errorf("Secret %s 的键 %s 已不在当前 spec 中", secret.Name, k)
+			}
+			secret.StringData[k] = value
+		}
+		for j := range secret.Files {
+			if secret.Files[j].Content != RedactedSecretValue {
+				continue
+			}
+			content, ok := files[secret.Name][secret.Files[j].Name]
+			if !ok {
+				return fmt.Errorf("Secret %s 的文件 %s 已不在当前 spec 中", secret.Name, secret.Files[j].Name)
+			}
+			secret.Files[j].Content = content
+		}
+	}
+	return nil
+}
+
+// specFields 把 spec 转换为通用的 JSON 结构，便于逐字段比较
+func specFields(spec *appsv1alpha1.KubeAppSpec) (map[string]interface{}, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// DiffSpecs 逐字段比较两个 spec 的 JSON 结构，返回按路径排序的变化。old 为 nil 时（第一个版本）返回 nil
+func DiffSpecs(old, new map[string]interface{}) []SpecChange {
+	if old == nil {
+		return nil
+	}
+	var changes []SpecChange
+	diffValues("", old, new, &changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes
+}
+
+// diffValues 递归比较 map 和数组，其他值不同时记录为一个变化
+func diffValues(path string, old, new interface{}, changes *[]SpecChange) {
+	oldMap, oldIsMap := old.(map[string]interface{})
+	newMap, newIsMap := new.(map[string]interface{})
+	if oldIsMap && newIsMap {
+		keys := map[string]bool{}
+		for k := range oldMap {
+			keys[k] = true
+		}
+		for k := range newMap {
+			keys[k] = true
+		}
+		for k := range keys {
+			diffValues(joinPath(path, k), oldMap[k], newMap[k], changes)
+		}
+		return
+	}
+
+	oldList, oldIsList := old.([]interface{})
+	newList, newIsList := new.([]interface{})
+	if oldIsList && newIsList {
+		for i := 0; i < len(oldList) || i < len(newList); i++ {
+			var o, n interface{}
+			if i < len(oldList) {
+				o = oldList[i]
+			}
+			if i < len(newList) {
+				n = newList[i]
+			}
+			diffValues(path+"["+strconv.Itoa(i)+"]", o, n, changes)
+		}
+		return
+	}
+
+	if !reflect.DeepEqual(old, new) {
+		*changes = append(*changes, SpecChange{Path: path, Old: old, New: new})
+	}
+}
+
+// joinPath 拼接字段路径
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return strings.Join([]string{path, key}, ".")
+}
@@ -8,7 +8,10 @@ import (
     corev1 "k8s.io/api/core/v1"
     metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
     "k8s.io/apimachinery/pkg/util/intstr"
+    "k8s.io/apimachinery/pkg/util/validation"
+    "net"
     "sigs.k8s.io/controller-runtime/pkg/client"
+    "strings"
     "time"
 
     // 添加日志依赖
@@ -21,29 +24,41 @@ type ServiceInfo struct {
     ClusterIP  string            `json:"cluster_ip"`
     Type       string            `json:"type"`
     Ports      []string           `json:"ports"`
+    PortDetails []ServicePortInfo `json:"port_details"`
     Selector   string `json:"selector"`
     CreatedAt  string            `json:"created_at"`
     Age        string            `json:"age"`
 }
 
+// ServicePortInfo 是 ServiceInfo 中的单个端口，多端口 Service 的每个端口一行
+type ServicePortInfo struct {
+    Name       string `json:"name"`
+    Port       int32  `json:"port"`
+    TargetPort string `json:"target_port"`
+    NodePort   int32  `json:"node_port,omitempty"`
+    Protocol   string `json:"protocol"`
+}
+
 // 创建日志记录器
 var log_svc = logf.Log.WithName("service-creator")
 
+// ExtraServiceLabel 标记由 spec.extraServices 生成的附加 Service
+const ExtraServiceLabel = "kubeapp.io/extra-service"
+
+// maxSessionAffinitySeconds 是 API server 允许的 ClientIP 会话保持最长时间（1 天）
+const maxSessionAffinitySeconds = 86400
 
 
 
 
-// 新增：DeleteService 删除对应的 Service（当 enableService == false 时调用）
+
+// 新增：DeleteService 删除对应的 Service（当 enableService == false 时调用），不由该 KubeApp 控制的同名 Service 会被保留
 
 func DeleteService(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
-    name := KubeApp.Spec.Service.Name
-    if name == "" {
-        name = KubeApp.Name
-    }
     svc := &corev1.Service{}
-    svc.SetName(name)
+    svc.SetName(ServiceName(KubeApp))
     svc.SetNamespace(namespace)
-    return utils.DeleteIfExists(ctx, cli, svc)
+    return utils.DeleteIfControlled(ctx, cli, svc, KubeApp)
 }
 
 
@@ -66,35 +81,112 @@ func NewService(KubeApp *appsv1alpha1.KubeApp, namespace string) (*corev1.Servic
     }
 
     // 3. 创建 Service 对象
+    service := buildService(KubeApp, KubeApp.Spec.Service, namespace)
+
+    log_svc.Info("Service 创建成功", "名称", service.Name,"命名空间", service.Namespace, "端口数量", len(service.Spec.Ports))
+
+    return service, nil
+}
+
+// NewExtraServices 创建 spec.extraServices 中的附加 Service，选择器与主 Service 相同。
+// 附加 Service 带 ExtraServiceLabel 标签，从 spec 中移除后据此清理
+func NewExtraServices(KubeApp *appsv1alpha1.KubeApp, namespace string) ([]*corev1.Service, error) {
+    if err := validateServiceParams(KubeApp); err != nil {
+        return nil, err
+    }
+
+    var services []*corev1.Service
+    for i := range KubeApp.Spec.ExtraServices {
+        spec := &KubeApp.Spec.ExtraServices[i]
+        if err := validateServiceSpec(spec); err != nil {
+            log_svc.Error(err, "附加 Service 规格验证失败", "KubeApp名称", KubeApp.Name, "Service名称", spec.Name)
+            return nil, err
+        }
+        service := buildService(KubeApp, spec, namespace)
+        service.Labels[ExtraServiceLabel] = "true"
+        services = append(services, service)
+        log_svc.Info("附加 Service 创建成功", "名称", service.Name, "命名空间", namespace, "端口数量", len(service.Spec.Ports))
+    }
+    return services, nil
+}
+
+// buildService 根据 ServiceSpec 构建 Service，选择器使用 Deployment 的 app 标签
+func buildService(KubeApp *appsv1alpha1.KubeApp, spec *appsv1alpha1.ServiceSpec, namespace string) *corev1.Service {
     service := &corev1.Service{
+        TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Service"},
         ObjectMeta: metav1.ObjectMeta{
-            Name:      KubeApp.Spec.Service.Name,
+            Name:      spec.Name,
             Namespace: namespace,
-            // 添加额外的标签和注解
+            // 添加额外的标签和注解，spec 中的注解（如云厂商 LoadBalancer 配置）优先
             Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
-            Annotations: KubeApp.Annotations,
+            Annotations: utils.MergeMaps(childAnnotations(KubeApp), spec.Annotations),
         },
         Spec: corev1.ServiceSpec{
             // 选择器使用 Deployment 的标签
-            Selector: map[string]string{"app": KubeApp.Spec.Deployment.Name},
-            Ports: []corev1.ServicePort{
-                {
-                    // 端口配置
-                    Port:       utils.NormalizePort(KubeApp.Spec.Service.Port),
-                    TargetPort: intstr.FromInt(int(utils.NormalizePort(KubeApp.Spec.Service.TargetPort))),
-                    // 可选：添加协议和名称
-                    Protocol: corev1.ProtocolTCP,
-                    Name:     fmt.Sprintf("%s-port", KubeApp.Spec.Service.Name),
-                },
-            },
-            // 可选：服务类型
-            Type: determineServiceType(KubeApp.Spec.Service),
+            Selector:                 map[string]string{"app": KubeApp.Spec.Deployment.Name},
+            Ports:                    prepareServicePorts(spec),
+            Type:                     determineServiceType(spec),
+            SessionAffinity:          spec.SessionAffinity,
+            ExternalTrafficPolicy:    spec.ExternalTrafficPolicy,
+            LoadBalancerSourceRanges: spec.LoadBalancerSourceRanges,
         },
     }
 
-    log_svc.Info("Service 创建成功", "名称", service.Name,"命名空间", service.Namespace)
+    // headless：clusterIP 为 None，DNS 直接返回 Pod IP
+    if spec.Headless {
+        service.Spec.ClusterIP = corev1.ClusterIPNone
+    }
+    if spec.SessionAffinityTimeoutSeconds != nil {
+        service.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+            ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: spec.SessionAffinityTimeoutSeconds},
+        }
+    }
+    return service
+}
 
-    return service, nil
+// prepareServicePorts 构建端口列表：配置了 ports 时逐个转换（协议默认 TCP），
+// 否则沿用单端口 port / targetPort 的写法
+func prepareServicePorts(spec *appsv1alpha1.ServiceSpec) []corev1.ServicePort {
+    if len(spec.Ports) == 0 {
+        return []corev1.ServicePort{
+            {
+                // 端口配置
+                Port:       utils.NormalizePort(spec.Port),
+                TargetPort: intstr.FromInt(int(utils.NormalizePort(spec.TargetPort))),
+                // 可选：添加协议和名称
+                Protocol: corev1.ProtocolTCP,
+                Name:     fmt.Sprintf("%s-port", spec.Name),
+            },
+        }
+    }
+
+    ports := make([]corev1.ServicePort, 0, len(spec.Ports))
+    for _, p := range spec.Ports {
+        protocol := p.Protocol
+        if protocol == "" {
+            protocol = corev1.ProtocolTCP
+        }
+        ports = append(ports, corev1.ServicePort{
+            Name:       p.Name,
+            Port:       p.Port,
+            TargetPort: intstr.FromInt32(p.TargetPort),
+            Protocol:   protocol,
+        })
+    }
+    log_svc.V(1).Info("配置 Service 端口", "Service名称", spec.Name, "数量", len(ports))
+    return ports
+}
+
+// ServicePortNumbers 返回 Service 暴露的全部端口号，供 Ingress 后端校验使用
+func ServicePortNumbers(spec *appsv1alpha1.ServiceSpec) []int32 {
+    if len(spec.Ports) == 0 {
+        return []int32{spec.Port}
+    }
+    numbers := make([]int32, 0, len(spec.Ports))
+    for _, p := range spec.Ports {
+        numbers = append(numbers, p.Port)
+    }
+    return numbers
 }
 
 // validateServiceParams 验证 KubeApp 参数
@@ -124,15 +216,98 @@ func validateServiceSpec(serviceSpec *appsv1alpha1.ServiceSpec) error {
         return fmt.Errorf("Service 名称不能为空")
     }
 
-    // 端口范围检查
-    if err := utils.ValidatePort(serviceSpec.Port); err != nil {
-        return fmt.Errorf("Service 端口验证失败: %v", err)
+    if len(serviceSpec.Ports) == 0 {
+        // 端口范围检查
+        if err := utils.ValidatePort(serviceSpec.Port); err != nil {
+            return fmt.Errorf("Service 端口验证失败: %v", err)
+        }
+
+        if err := utils.ValidatePort(serviceSpec.TargetPort); err != nil {
+            return fmt.Errorf("Service 目标端口验证失败: %v", err)
+        }
+    } else if err := validateServicePorts(serviceSpec.Ports); err != nil {
+        return err
     }
 
-    if err := utils.ValidatePort(serviceSpec.TargetPort); err != nil {
-        return fmt.Errorf("Service 目标端口验证失败: %v", err)
+    return validateServiceOptions(serviceSpec)
+}
+
+// validateServicePorts 校验多端口配置：多个端口时必须命名，名称和 端口/协议 组合不能重复
+func validateServicePorts(ports []appsv1alpha1.ServicePort) error {
+    names := map[string]bool{}
+    numbers := map[string]bool{}
+    for _, p := range ports {
+        if err := utils.ValidatePort(p.Port); err != nil {
+            return fmt.Errorf("Service 端口 %q 验证失败: %v", p.Name, err)
+        }
+        if err := utils.ValidatePort(p.TargetPort); err != nil {
+            return fmt.Errorf("Service 端口 %q 的目标端口验证失败: %v", p.Name, err)
+        }
+
+        switch p.Protocol {
+        case "", corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+        default:
+            return fmt.Errorf("Service 端口 %q 的协议 %s 不支持，只能是 TCP、UDP 或 SCTP", p.Name, p.Protocol)
+        }
+
+        if p.Name == "" {
+            if len(ports) > 1 {
+                return fmt.Errorf("Service 有多个端口时每个端口都必须设置 name")
+            }
+        } else {
+            if errs := validation.IsDNS1123Label(p.Name); len(errs) > 0 {
+                return fmt.Errorf("Service 端口名称 %q 不合法: %s", p.Name, strings.Join(errs, ", "))
+            }
+            if names[p.Name] {
+                return fmt.Errorf("Service 端口名称 %q 重复", p.Name)
+            }
+            names[p.Name] = true
+        }
+
+        protocol := p.Protocol
+        if protocol == "" {
+            protocol = corev1.ProtocolTCP
+        }
+        key := fmt.Sprintf("%d/%s", p.Port, protocol)
+        if numbers[key] {
+            return fmt.Errorf("Service 端口 %s 重复", key)
+        }
+        numbers[key] = true
+    }
+    return nil
+}
+
+// validateServiceOptions 校验 headless、会话保持、externalTrafficPolicy 和 LoadBalancer 来源地址与服务类型是否匹配
+func validateServiceOptions(serviceSpec *appsv1alpha1.ServiceSpec) error {
+    serviceType := determineServiceType(serviceSpec)
+    external := serviceType == corev1.ServiceTypeNodePort || serviceType == corev1.ServiceTypeLoadBalancer
+
+    if serviceSpec.Headless && external {
+        return fmt.Errorf("headless Service 只能是 ClusterIP 类型，当前为 %s", serviceType)
+    }
+    if serviceSpec.ExternalTrafficPolicy != "" && !external {
+        return fmt.Errorf("externalTrafficPolicy 只能用于 NodePort 或 LoadBalancer 类型的 Service")
     }
 
+    if serviceSpec.SessionAffinityTimeoutSeconds != nil {
+        if serviceSpec.SessionAffinity != corev1.ServiceAffinityClientIP {
+            return fmt.Errorf("sessionAffinityTimeoutSeconds 只能与 sessionAffinity ClientIP 一起使用")
+        }
+        if timeout := *serviceSpec.SessionAffinityTimeoutSeconds; timeout < 1 || timeout > maxSessionAffinitySeconds {
+            return fmt.Errorf("sessionAffinityTimeoutSeconds 必须在 1 到 %d 之间，当前为 %d", maxSessionAffinitySeconds, timeout)
+        }
+    }
+
+    if len(serviceSpec.LoadBalancerSourceRanges) > 0 {
+        if serviceType != corev1.ServiceTypeLoadBalancer {
+            return fmt.Errorf("loadBalancerSourceRanges 只能用于 LoadBalancer 类型的 Service")
+        }
+        for _, cidr := range serviceSpec.LoadBalancerSourceRanges {
+            if _, _, err := net.ParseCIDR(strings.TrimSpace(cidr)); err != nil {
+                return fmt.Errorf("loadBalancerSourceRanges 中的 %q 不是合法的 CIDR", cidr)
+            }
+        }
+    }
     return nil
 }
 
@@ -178,14 +353,22 @@ func ListServices(namespace string) ([]ServiceInfo, error) {
         // 计算 AGE
         age := utils.FormatSvcAge(time.Since(svc.CreationTimestamp.Time))
 
-        // 拼接 Ports
+        // 拼接 Ports，多端口 Service 列出全部端口
         var ports []string
+        var portDetails []ServicePortInfo
         for _, p := range svc.Spec.Ports {
             if p.NodePort > 0 {
                 ports = append(ports, fmt.Sprintf("%d:%d/%s", p.Port, p.NodePort, p.Protocol))
             } else {
                 ports = append(ports, fmt.Sprintf("%d/%s", p.Port, p.Protocol))
             }
+            portDetails = append(portDetails, ServicePortInfo{
+                Name:       p.Name,
+                Port:       p.Port,
+                TargetPort: p.TargetPort.String(),
+                NodePort:   p.NodePort,
+                Protocol:   string(p.Protocol),
+            })
         }
         labelStr := utils.FormatLabels(svc.Spec.Selector)
         result = append(result, ServiceInfo{
@@ -194,6 +377,7 @@ func ListServices(namespace string) ([]ServiceInfo, error) {
             ClusterIP: svc.Spec.ClusterIP,
             Type:      string(svc.Spec.Type),
             Ports:     ports,
+            PortDetails: portDetails,
             Selector:  labelStr,
             CreatedAt: createdAt,
             Age:     age,
@@ -0,0 +1,143 @@
+package define
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_sa = logf.Log.WithName("serviceaccount-creator")
+
+// ServiceAccountConfigured 判断 KubeApp 是否配置了 spec.serviceAccount
+func ServiceAccountConfigured(KubeApp *appsv1alpha1.KubeApp) bool {
+	return KubeApp.Spec.ServiceAccount != nil
+}
+
+// ServiceAccountRoleConfigured 判断是否需要为 ServiceAccount 生成 Role / RoleBinding
+func ServiceAccountRoleConfigured(KubeApp *appsv1alpha1.KubeApp) bool {
+	return ServiceAccountConfigured(KubeApp) && len(KubeApp.Spec.ServiceAccount.Rules) > 0
+}
+
+// DeleteServiceAccount 删除 KubeApp 对应的 ServiceAccount。未配置 serviceAccount 时名称与 KubeApp 相同，
+// 同名的手工 ServiceAccount 不由该 KubeApp 控制，会被保留
+func DeleteServiceAccount(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	sa := &corev1.ServiceAccount{}
+	sa.SetName(ServiceAccountName(KubeApp))
+	sa.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, sa, KubeApp)
+}
+
+// DeleteServiceAccountRole 先删除 RoleBinding 再删除 Role，只删除由该 KubeApp 控制的对象
+func DeleteServiceAccountRole(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	binding := &rbacv1.RoleBinding{}
+	binding.SetName(ServiceAccountName(KubeApp))
+	binding.SetNamespace(namespace)
+	if err := utils.DeleteIfControlled(ctx, cli, binding, KubeApp); err != nil {
+		return err
+	}
+	role := &rbacv1.Role{}
+	role.SetName(ServiceAccountName(KubeApp))
+	role.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, role, KubeApp)
+}
+
+// NewServiceAccount 根据 spec.serviceAccount 创建 ServiceAccount，注解用于绑定云厂商 IAM 身份
+func NewServiceAccount(KubeApp *appsv1alpha1.KubeApp, namespace string) (*corev1.ServiceAccount, error) {
+	if KubeApp == nil || !ServiceAccountConfigured(KubeApp) {
+		return nil, fmt.Errorf("KubeApp 未配置 serviceAccount")
+	}
+	spec := KubeApp.Spec.ServiceAccount
+	if err := validateServiceAccountSpec(spec); err != nil {
+		log_sa.Error(err, "ServiceAccount 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+
+	sa := &corev1.ServiceAccount{
+		TypeMeta:                     metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "ServiceAccount"},
+		ObjectMeta:                   serviceAccountObjectMeta(KubeApp, namespace),
+		AutomountServiceAccountToken: spec.AutomountServiceAccountToken,
+	}
+	sa.Annotations = utils.MergeMaps(sa.Annotations, spec.Annotations)
+
+	log_sa.Info("ServiceAccount 创建成功", "名称", sa.Name, "命名空间", namespace, "注解数量", len(spec.Annotations))
+	return sa, nil
+}
+
+// NewServiceAccountRole 根据 spec.serviceAccount.rules 创建与 ServiceAccount 同名的 Role 和 RoleBinding
+func NewServiceAccountRole(KubeApp *appsv1alpha1.KubeApp, namespace string) (*rbacv1.Role, *rbacv1.RoleBinding, error) {
+	if KubeApp == nil || !ServiceAccountRoleConfigured(KubeApp) {
+		return nil, nil, fmt.Errorf("KubeApp 未配置 serviceAccount.rules")
+	}
+	if err := validateServiceAccountSpec(KubeApp.Spec.ServiceAccount); err != nil {
+		log_sa.Error(err, "Role 参数验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, nil, err
+	}
+
+	name := ServiceAccountName(KubeApp)
+	role := &rbacv1.Role{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+		ObjectMeta: serviceAccountObjectMeta(KubeApp, namespace),
+		Rules:      KubeApp.Spec.ServiceAccount.Rules,
+	}
+	binding := &rbacv1.RoleBinding{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+		ObjectMeta: serviceAccountObjectMeta(KubeApp, namespace),
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      name,
+			Namespace: namespace,
+		}},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     name,
+		},
+	}
+
+	log_sa.Info("Role 与 RoleBinding 创建成功", "名称", name, "命名空间", namespace, "规则数量", len(role.Rules))
+	return role, binding, nil
+}
+
+// serviceAccountObjectMeta 返回 ServiceAccount、Role 和 RoleBinding 共用的元数据
+func serviceAccountObjectMeta(KubeApp *appsv1alpha1.KubeApp, namespace string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Name:        ServiceAccountName(KubeApp),
+		Namespace:   namespace,
+		Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+		Annotations: childAnnotations(KubeApp),
+	}
+}
+
+// validateServiceAccountSpec 校验名称和 Role 规则：Role 只能授予命名空间内资源的权限，不支持 nonResourceURLs
+func validateServiceAccountSpec(spec *appsv1alpha1.ServiceAccountSpec) error {
+	if spec == nil {
+		return fmt.Errorf("KubeApp 未配置 serviceAccount")
+	}
+	if spec.Name != "" {
+		if errs := validation.IsDNS1123Subdomain(spec.Name); len(errs) > 0 {
+			return fmt.Errorf("ServiceAccount 名称 %q 不合法: %s", spec.Name, strings.Join(errs, ", "))
+		}
+	}
+	for i, rule := range spec.Rules {
+		if len(rule.Verbs) == 0 {
+			return fmt.Errorf("第 %d 条规则必须指定 verbs", i+1)
+		}
+		if len(rule.NonResourceURLs) > 0 {
+			return fmt.Errorf("第 %d 条规则不能使用 nonResourceURLs，Role 只能授予命名空间内资源的权限", i+1)
+		}
+		if len(rule.APIGroups) == 0 || len(rule.Resources) == 0 {
+			return fmt.Errorf("第 %d 条规则必须指定 apiGroups 和 resources（核心组使用 \"\"）", i+1)
+		}
+	}
+	return nil
+}
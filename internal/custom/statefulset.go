@@ -0,0 +1,177 @@
+package define
+
+import (
+	"context"
+	"fmt"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	"github.com/k8s/kube-app-operator/internal/pkg/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// 创建日志记录器
+var log_sts = logf.Log.WithName("statefulset-creator")
+
+// DeleteStatefulSet 删除 KubeApp 对应的 StatefulSet。
+// volumeClaimTemplates 生成的 PVC 不会随之删除（StatefulSet 默认保留），需要管理员手动清理
+func DeleteStatefulSet(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	sts := &appsv1.StatefulSet{}
+	sts.SetName(DeploymentName(KubeApp))
+	sts.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, sts, KubeApp)
+}
+
+// DeleteHeadlessService 删除 StatefulSet 的 headless 管理 Service
+func DeleteHeadlessService(ctx context.Context, cli client.Client, KubeApp *appsv1alpha1.KubeApp, namespace string) error {
+	svc := &corev1.Service{}
+	svc.SetName(HeadlessServiceName(KubeApp))
+	svc.SetNamespace(namespace)
+	return utils.DeleteIfControlled(ctx, cli, svc, KubeApp)
+}
+
+// NewStatefulSet 根据 KubeApp 创建 StatefulSet，容器、卷和亲和性与 Deployment 使用同一套构建逻辑
+func NewStatefulSet(KubeApp *appsv1alpha1.KubeApp, namespace string) (*appsv1.StatefulSet, error) {
+	if KubeApp == nil {
+		return nil, fmt.Errorf("KubeApp 对象不能为空")
+	}
+
+	log_sts.Info("开始创建 StatefulSet", "KubeApp名称", KubeApp.Name, "命名空间", namespace)
+
+	if KubeApp.Spec.Deployment == nil {
+		return nil, fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+	if err := validateDeploymentSpec(KubeApp.Spec.Deployment); err != nil {
+		log_sts.Error(err, "StatefulSet 容器规格验证失败", "KubeApp名称", KubeApp.Name)
+		return nil, err
+	}
+
+	replicas := workloadReplicas(KubeApp)
+
+	podManagementPolicy := podManagementPolicy(KubeApp.Spec.StatefulSet)
+	var claimTemplates []corev1.PersistentVolumeClaim
+	if sts := KubeApp.Spec.StatefulSet; sts != nil {
+		var err error
+		if claimTemplates, err = prepareVolumeClaimTemplates(sts.VolumeClaimTemplates); err != nil {
+			log_sts.Error(err, "volumeClaimTemplates 构建失败", "KubeApp名称", KubeApp.Name)
+			return nil, err
+		}
+	}
+
+	statefulSet := &appsv1.StatefulSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: appsv1.SchemeGroupVersion.String(), Kind: "StatefulSet"},
+		ObjectMeta: workloadObjectMeta(KubeApp, namespace),
+		Spec: appsv1.StatefulSetSpec{
+			Replicas:    replicas,
+			ServiceName: HeadlessServiceName(KubeApp),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": KubeApp.Spec.Deployment.Name,
+				},
+			},
+			Template:             preparePodTemplate(KubeApp),
+			VolumeClaimTemplates: claimTemplates,
+			PodManagementPolicy:  podManagementPolicy,
+		},
+	}
+
+	log_sts.Info("StatefulSet 创建成功", "名称", statefulSet.Name, "命名空间", statefulSet.Namespace,
+		"podManagementPolicy", podManagementPolicy, "volumeClaimTemplates数量", len(claimTemplates))
+	return statefulSet, nil
+}
+
+// NewHeadlessService 创建 StatefulSet 的 headless 管理 Service，为每个副本提供稳定的 DNS 名称
+func NewHeadlessService(KubeApp *appsv1alpha1.KubeApp, namespace string) (*corev1.Service, error) {
+	if KubeApp == nil || KubeApp.Spec.Deployment == nil {
+		return nil, fmt.Errorf("KubeApp 的 Deployment 规格不能为空")
+	}
+
+	var ports []corev1.ServicePort
+	for _, p := range KubeApp.Spec.Deployment.Ports {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("port-%d", p.ContainerPort)
+		}
+		protocol := p.Protocol
+		if protocol == "" {
+			protocol = corev1.ProtocolTCP
+		}
+		ports = append(ports, corev1.ServicePort{
+			Name:       name,
+			Port:       p.ContainerPort,
+			TargetPort: intstr.FromInt(int(p.ContainerPort)),
+			Protocol:   protocol,
+		})
+	}
+
+	service := &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: corev1.SchemeGroupVersion.String(), Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        HeadlessServiceName(KubeApp),
+			Namespace:   namespace,
+			Labels:      utils.MergeMaps(KubeApp.Labels, map[string]string{"managed-by": "KubeApp-operator"}),
+			Annotations: childAnnotations(KubeApp),
+		},
+		Spec: corev1.ServiceSpec{
+			ClusterIP: corev1.ClusterIPNone,
+			Selector:  map[string]string{"app": KubeApp.Spec.Deployment.Name},
+			Ports:     ports,
+		},
+	}
+
+	log_sts.Info("headless Service 创建成功", "名称", service.Name, "命名空间", namespace, "端口数量", len(ports))
+	return service, nil
+}
+
+// podManagementPolicy 返回生效的 Pod 管理策略，未设置时为 OrderedReady
+func podManagementPolicy(sts *appsv1alpha1.StatefulSetSpec) appsv1.PodManagementPolicyType {
+	if sts == nil || sts.PodManagementPolicy == "" {
+		return appsv1.OrderedReadyPodManagement
+	}
+	return sts.PodManagementPolicy
+}
+
+// prepareVolumeClaimTemplates 把 volumeClaimTemplates 转换为 StatefulSet 的 PVC 模板
+func prepareVolumeClaimTemplates(templates []appsv1alpha1.VolumeClaimTemplate) ([]corev1.PersistentVolumeClaim, error) {
+	var claims []corev1.PersistentVolumeClaim
+	for _, t := range templates {
+		if err := validateVolumeClaimTemplate(&t); err != nil {
+			return nil, err
+		}
+		accessModes := t.AccessModes
+		if len(accessModes) == 0 {
+			accessModes = []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce}
+		}
+		claim := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: t.Name},
+			Spec: corev1.PersistentVolumeClaimSpec{
+				AccessModes: accessModes,
+				Resources: corev1.VolumeResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse(t.Storage)},
+				},
+			},
+		}
+		if t.StorageClassName != nil && *t.StorageClassName != "" {
+			claim.Spec.StorageClassName = t.StorageClassName
+		}
+		log_sts.V(1).Info("配置 volumeClaimTemplate", "名称", t.Name, "容量", t.Storage)
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
+
+// validateVolumeClaimTemplate 校验 volumeClaimTemplate 的名称和容量
+func validateVolumeClaimTemplate(t *appsv1alpha1.VolumeClaimTemplate) error {
+	if t.Name == "" {
+		return fmt.Errorf("volumeClaimTemplate 名称不能为空")
+	}
+	if _, err := resource.ParseQuantity(t.Storage); err != nil {
+		return fmt.Errorf("volumeClaimTemplate %s 的 storage 无效: %v", t.Name, err)
+	}
+	return nil
+}
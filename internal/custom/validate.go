@@ -0,0 +1,542 @@
+package define
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateKubeApp 在准入阶段校验 KubeApp：复用构建子资源时使用的校验函数，
+// 并补充只有看到整个 spec 才能发现的跨字段问题。返回带字段路径的错误列表。
+func ValidateKubeApp(KubeApp *appsv1alpha1.KubeApp) field.ErrorList {
+	var allErrs field.ErrorList
+	spec := &KubeApp.Spec
+	specPath := field.NewPath("spec")
+
+	allErrs = append(allErrs, validatePauseAnnotations(KubeApp)...)
+
+	if spec.EnableDeployment {
+		path := specPath.Child("deployment")
+		if spec.Deployment == nil {
+			allErrs = append(allErrs, field.Required(path, "enableDeployment 为 true 时必须配置 deployment"))
+		} else {
+			if err := validateDeploymentSpec(spec.Deployment); err != nil {
+				allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+			}
+			allErrs = append(allErrs, validateVolumeMounts(spec, path)...)
+			allErrs = append(allErrs, validateContainers(spec.Deployment, path)...)
+			if err := validateSecurityProfile(KubeApp); err != nil {
+				allErrs = append(allErrs, field.Invalid(path.Child("securityProfile"), field.OmitValueType{}, err.Error()))
+			}
+		}
+		switch spec.EffectiveWorkloadType() {
+		case appsv1alpha1.WorkloadDeployment:
+			if spec.Deployment != nil {
+				if err := validateDeploymentStrategy(spec.Deployment.Strategy); err != nil {
+					allErrs = append(allErrs, field.Invalid(path.Child("strategy"), field.OmitValueType{}, err.Error()))
+				}
+			}
+		case appsv1alpha1.WorkloadStatefulSet:
+			allErrs = append(allErrs, validateStatefulSet(KubeApp, specPath.Child("statefulSet"))...)
+		case appsv1alpha1.WorkloadDaemonSet:
+			if err := validateDaemonSetSpec(spec.DaemonSet); err != nil {
+				allErrs = append(allErrs, field.Invalid(specPath.Child("daemonSet", "maxUnavailable"), field.OmitValueType{}, err.Error()))
+			}
+		case appsv1alpha1.WorkloadCronJob:
+			allErrs = append(allErrs, validateCronJob(spec.CronJob, specPath.Child("cronJob"))...)
+		}
+		allErrs = append(allErrs, validateAutoscaling(KubeApp, specPath.Child("autoscaling"))...)
+		allErrs = append(allErrs, validateDisruptionBudget(KubeApp, specPath.Child("disruptionBudget"))...)
+	}
+
+	allErrs = append(allErrs, validateConfigs(spec, specPath)...)
+
+	if spec.EnableService {
+		path := specPath.Child("service")
+		if spec.Service == nil {
+			allErrs = append(allErrs, field.Required(path, "enableService 为 true 时必须配置 service"))
+		} else {
+			if err := validateServiceSpec(spec.Service); err != nil {
+				allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+			}
+			// Service 的 selector 取自 Deployment 名称
+			if spec.Deployment == nil {
+				allErrs = append(allErrs, field.Required(specPath.Child("deployment"), "Service 的选择器依赖 deployment.name，必须配置 deployment"))
+			}
+		}
+		allErrs = append(allErrs, validateExtraServices(KubeApp, specPath.Child("extraServices"))...)
+	}
+
+	if spec.EnableIngress {
+		path := specPath.Child("ingress")
+		if spec.Ingress == nil {
+			allErrs = append(allErrs, field.Required(path, "enableIngress 为 true 时必须配置 ingress"))
+		} else {
+			if err := validateIngressSpec(spec.Ingress); err != nil {
+				allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+			}
+			if cls := spec.Ingress.IngressClassName; cls != "" {
+				if err := ValidateIngressClassName(cls); err != nil {
+					allErrs = append(allErrs, field.Invalid(path.Child("ingressClassName"), cls, err.Error()))
+				}
+			}
+			allErrs = append(allErrs, validateIngressBackend(spec, path)...)
+		}
+	}
+
+	if spec.Routing != nil && spec.Routing.Gateway != nil {
+		path := specPath.Child("routing", "gateway")
+		if err := validateGatewayRoute(KubeApp); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+		}
+		allErrs = append(allErrs, validateRouteBackends(KubeApp, path)...)
+	}
+
+	if spec.ServiceAccount != nil {
+		if err := validateServiceAccountSpec(spec.ServiceAccount); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("serviceAccount"), field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	if spec.NetworkPolicy != nil {
+		if !spec.EnableDeployment || spec.Deployment == nil {
+			allErrs = append(allErrs, field.Required(specPath.Child("deployment"), "networkPolicy 按 deployment.name 选择 Pod，必须启用并配置 deployment"))
+		}
+		if err := validateNetworkPolicySpec(spec.NetworkPolicy); err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("networkPolicy"), field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	if spec.EnablePvc {
+		path := specPath.Child("pvc")
+		if spec.Pvc == nil {
+			allErrs = append(allErrs, field.Required(path, "enablePvc 为 true 时必须配置 pvc"))
+		} else if err := validatePvcSpec(spec.Pvc); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePauseAnnotations 校验暂停注解只能取 paused，kubeapp.io/rollout 只能用于 Deployment 工作负载
+func validatePauseAnnotations(KubeApp *appsv1alpha1.KubeApp) field.ErrorList {
+	var allErrs field.ErrorList
+	annotationsPath := field.NewPath("metadata", "annotations")
+	for _, key := range []string{appsv1alpha1.ReconcileAnnotation, appsv1alpha1.RolloutAnnotation} {
+		if value, ok := KubeApp.Annotations[key]; ok && value != appsv1alpha1.PausedValue {
+			allErrs = append(allErrs, field.NotSupported(annotationsPath.Key(key), value, []string{appsv1alpha1.PausedValue}))
+		}
+	}
+	if KubeApp.Annotations[appsv1alpha1.RolloutAnnotation] == appsv1alpha1.PausedValue &&
+		KubeApp.Spec.EffectiveWorkloadType() != appsv1alpha1.WorkloadDeployment {
+		allErrs = append(allErrs, field.Invalid(annotationsPath.Key(appsv1alpha1.RolloutAnnotation), appsv1alpha1.PausedValue,
+			fmt.Sprintf("只能暂停 Deployment 的滚动更新，当前工作负载为 %s", KubeApp.Spec.EffectiveWorkloadType())))
+	}
+	return allErrs
+}
+
+// validateVolumeMounts 检查主容器、init 容器和 sidecar 的每个 volumeMount 都引用了已声明的 volume
+// （StatefulSet 还可以引用 volumeClaimTemplates），且同一容器内挂载路径不重复
+func validateVolumeMounts(kubeAppSpec *appsv1alpha1.KubeAppSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	spec := kubeAppSpec.Deployment
+
+	volumes := make(map[string]bool, len(spec.Volumes))
+	for i, v := range spec.Volumes {
+		if volumes[v.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("volumes").Index(i).Child("name"), v.Name))
+		}
+		volumes[v.Name] = true
+	}
+	if kubeAppSpec.EffectiveWorkloadType() == appsv1alpha1.WorkloadStatefulSet && kubeAppSpec.StatefulSet != nil {
+		for _, t := range kubeAppSpec.StatefulSet.VolumeClaimTemplates {
+			volumes[t.Name] = true
+		}
+	}
+
+	allErrs = append(allErrs, validateContainerMounts(spec.VolumeMounts, volumes, path.Child("volumeMounts"))...)
+	for i, c := range spec.InitContainers {
+		allErrs = append(allErrs, validateContainerMounts(c.VolumeMounts, volumes, path.Child("initContainers").Index(i).Child("volumeMounts"))...)
+	}
+	for i, c := range spec.Sidecars {
+		allErrs = append(allErrs, validateContainerMounts(c.VolumeMounts, volumes, path.Child("sidecars").Index(i).Child("volumeMounts"))...)
+	}
+	return allErrs
+}
+
+// validateContainerMounts 校验单个容器的 volumeMounts
+func validateContainerMounts(volumeMounts []appsv1alpha1.VolumeMount, volumes map[string]bool, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	mountPaths := make(map[string]bool, len(volumeMounts))
+	for i, vm := range volumeMounts {
+		mountPath := path.Index(i)
+		if !volumes[vm.Name] {
+			allErrs = append(allErrs, field.NotFound(mountPath.Child("name"), vm.Name))
+		}
+		if mountPaths[vm.MountPath] {
+			allErrs = append(allErrs, field.Duplicate(mountPath.Child("mountPath"), vm.MountPath))
+		}
+		mountPaths[vm.MountPath] = true
+	}
+	return allErrs
+}
+
+// validateContainers 检查 init 容器和 sidecar：名称与镜像必填、Pod 内容器名唯一，
+// 普通 init 容器不能配置探针（只有原生 sidecar 可以）
+func validateContainers(spec *appsv1alpha1.DeploymentSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := map[string]bool{spec.Name: true}
+
+	check := func(c *appsv1alpha1.ContainerSpec, p *field.Path, initContainer bool) {
+		switch {
+		case c.Name == "":
+			allErrs = append(allErrs, field.Required(p.Child("name"), "容器名称不能为空"))
+		case names[c.Name]:
+			allErrs = append(allErrs, field.Duplicate(p.Child("name"), c.Name))
+		}
+		names[c.Name] = true
+		if c.Image == "" {
+			allErrs = append(allErrs, field.Required(p.Child("image"), "容器镜像不能为空"))
+		}
+		if c.RestartPolicy != nil && *c.RestartPolicy != corev1.ContainerRestartPolicyAlways {
+			allErrs = append(allErrs, field.NotSupported(p.Child("restartPolicy"), *c.RestartPolicy,
+				[]corev1.ContainerRestartPolicy{corev1.ContainerRestartPolicyAlways}))
+		}
+		if initContainer && !isNativeSidecar(c) {
+			probes := []struct {
+				name string
+				set  bool
+			}{
+				{"livenessProbe", c.LivenessProbe != nil},
+				{"readinessProbe", c.ReadinessProbe != nil},
+				{"startupProbe", c.StartupProbe != nil},
+			}
+			for _, probe := range probes {
+				if probe.set {
+					allErrs = append(allErrs, field.Forbidden(p.Child(probe.name), "普通 init 容器不支持探针，需要探针请设置 restartPolicy: Always"))
+				}
+			}
+		}
+	}
+
+	for i := range spec.InitContainers {
+		check(&spec.InitContainers[i], path.Child("initContainers").Index(i), true)
+	}
+	for i := range spec.Sidecars {
+		check(&spec.Sidecars[i], path.Child("sidecars").Index(i), false)
+	}
+	return allErrs
+}
+
+// serviceBackendCheck 返回校验后端端口的函数：后端是 spec.service 或某个附加 Service 时，
+// 端口必须是该 Service 暴露的端口之一；指向其他 Service 时不校验。enableService 为 false 时返回 nil
+func serviceBackendCheck(spec *appsv1alpha1.KubeAppSpec, allErrs *field.ErrorList) func(serviceName string, servicePort int32, portPath *field.Path) {
+	if !spec.EnableService {
+		return nil
+	}
+	services := map[string]*appsv1alpha1.ServiceSpec{}
+	if spec.Service != nil {
+		services[spec.Service.Name] = spec.Service
+	}
+	for i := range spec.ExtraServices {
+		services[spec.ExtraServices[i].Name] = &spec.ExtraServices[i]
+	}
+
+	return func(serviceName string, servicePort int32, portPath *field.Path) {
+		svc, ok := services[serviceName]
+		if !ok {
+			return
+		}
+		ports := ServicePortNumbers(svc)
+		for _, port := range ports {
+			if port == servicePort {
+				return
+			}
+		}
+		*allErrs = append(*allErrs, field.Invalid(portPath, servicePort,
+			fmt.Sprintf("必须是 Service %s 暴露的端口之一 %v", svc.Name, ports)))
+	}
+}
+
+// validateIngressBackend 检查 Ingress 路由指向本 KubeApp 的 Service（含附加 Service）时，servicePort 是该 Service 暴露的端口之一
+func validateIngressBackend(spec *appsv1alpha1.KubeAppSpec, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	check := serviceBackendCheck(spec, &allErrs)
+	if check == nil {
+		return nil
+	}
+
+	if len(spec.Ingress.Rules) == 0 {
+		check(spec.Ingress.ServiceName, spec.Ingress.ServicePort, path.Child("service_port"))
+		return allErrs
+	}
+	for i, r := range spec.Ingress.Rules {
+		for j, p := range r.Paths {
+			check(p.ServiceName, p.ServicePort, path.Child("rules").Index(i).Child("paths").Index(j).Child("servicePort"))
+		}
+	}
+	return allErrs
+}
+
+// validateRouteBackends 与 validateIngressBackend 相同，检查 HTTPRoute backendRefs 的端口；未设置的端口由 EffectiveHTTPRouteRules 取默认值
+func validateRouteBackends(KubeApp *appsv1alpha1.KubeApp, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	check := serviceBackendCheck(&KubeApp.Spec, &allErrs)
+	if check == nil {
+		return nil
+	}
+
+	for i, r := range KubeApp.Spec.Routing.Gateway.Rules {
+		for j, b := range r.BackendRefs {
+			if b.Port == 0 {
+				continue
+			}
+			name := b.Name
+			if name == "" {
+				name = ServiceName(KubeApp)
+			}
+			check(name, b.Port, path.Child("rules").Index(i).Child("backendRefs").Index(j).Child("port"))
+		}
+	}
+	return allErrs
+}
+
+// validateExtraServices 校验附加 Service：规格合法，名称互不重复，也不与主 Service 或 StatefulSet 的 headless Service 重名
+func validateExtraServices(KubeApp *appsv1alpha1.KubeApp, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	if KubeApp.Spec.Service != nil {
+		seen[KubeApp.Spec.Service.Name] = true
+	}
+	if KubeApp.Spec.EffectiveWorkloadType() == appsv1alpha1.WorkloadStatefulSet {
+		seen[HeadlessServiceName(KubeApp)] = true
+	}
+	for i := range KubeApp.Spec.ExtraServices {
+		svc := &KubeApp.Spec.ExtraServices[i]
+		if err := validateServiceSpec(svc); err != nil {
+			allErrs = append(allErrs, field.Invalid(path.Index(i), field.OmitValueType{}, err.Error()))
+			continue
+		}
+		if seen[svc.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Index(i).Child("name"), svc.Name))
+		}
+		seen[svc.Name] = true
+	}
+	return allErrs
+}
+
+// validateStatefulSet 校验 StatefulSet 配置：volumeClaimTemplates 名称唯一、不与 volumes 重名且容量合法，
+// headless 管理 Service 不能与 spec.service 同名（后者带 ClusterIP，不能作为管理 Service）
+func validateStatefulSet(KubeApp *appsv1alpha1.KubeApp, path *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	spec := &KubeApp.Spec
+	sts := spec.StatefulSet
+	if sts == nil {
+		return nil
+	}
+
+	volumes := map[string]bool{}
+	if spec.Deployment != nil {
+		for _, v := range spec.Deployment.Volumes {
+			volumes[v.Name] = true
+		}
+	}
+	templates := make(map[string]bool, len(sts.VolumeClaimTemplates))
+	for i, t := range sts.VolumeClaimTemplates {
+		p := path.Child("volumeClaimTemplates").Index(i)
+		switch {
+		case templates[t.Name]:
+			allErrs = append(allErrs, field.Duplicate(p.Child("name"), t.Name))
+		case volumes[t.Name]:
+			allErrs = append(allErrs, field.Invalid(p.Child("name"), t.Name, "与 deployment.volumes 中的卷重名"))
+		}
+		templates[t.Name] = true
+		if err := validateVolumeClaimTemplate(&t); err != nil {
+			allErrs = append(allErrs, field.Invalid(p, field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	if spec.EnableService && spec.Service != nil && spec.Service.Name == HeadlessServiceName(KubeApp) {
+		allErrs = append(allErrs, field.Invalid(path.Child("serviceName"), spec.Service.Name, "不能与 spec.service.name 相同"))
+	}
+	return allErrs
+}
+
+// validateAutoscaling 校验 autoscaling 只用于 Deployment / StatefulSet，且 maxReplicas 不小于 minReplicas
+func validateAutoscaling(KubeApp *appsv1alpha1.KubeApp, path *field.Path) field.ErrorList {
+	spec := KubeApp.Spec.Autoscaling
+	if spec == nil || KubeApp.Spec.Deployment == nil {
+		return nil
+	}
+	if !AutoscalingEnabled(KubeApp) {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("workloadType %s 不支持 autoscaling", KubeApp.Spec.EffectiveWorkloadType()))}
+	}
+	if err := validateAutoscalingSpec(spec, autoscalingMinReplicas(KubeApp)); err != nil {
+		return field.ErrorList{field.Invalid(path.Child("maxReplicas"), spec.MaxReplicas, err.Error())}
+	}
+	return nil
+}
+
+// validateDisruptionBudget 校验 disruptionBudget 只用于 Deployment / StatefulSet，且 minAvailable / maxUnavailable 合法
+func validateDisruptionBudget(KubeApp *appsv1alpha1.KubeApp, path *field.Path) field.ErrorList {
+	spec := KubeApp.Spec.DisruptionBudget
+	if spec == nil || KubeApp.Spec.Deployment == nil {
+		return nil
+	}
+	if !DisruptionBudgetConfigured(KubeApp) {
+		return field.ErrorList{field.Forbidden(path, fmt.Sprintf("workloadType %s 不支持 disruptionBudget", KubeApp.Spec.EffectiveWorkloadType()))}
+	}
+	if err := validateDisruptionBudgetSpec(spec); err != nil {
+		return field.ErrorList{field.Invalid(path, field.OmitValueType{}, err.Error())}
+	}
+	return nil
+}
+
+// validateConfigs 校验 configMaps / secrets：名称在各自列表内唯一，key 合法且 data 与 files 之间不重复。
+// Secret 的错误不回显取值
+func validateConfigs(spec *appsv1alpha1.KubeAppSpec, specPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	seen := map[string]bool{}
+	for i, cm := range spec.ConfigMaps {
+		path := specPath.Child("configMaps").Index(i)
+		if seen[cm.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), cm.Name))
+			continue
+		}
+		seen[cm.Name] = true
+		if _, err := mergeConfigData(cm.Name, cm.Data, cm.Files); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+		}
+	}
+
+	seen = map[string]bool{}
+	for i, secret := range spec.Secrets {
+		path := specPath.Child("secrets").Index(i)
+		if seen[secret.Name] {
+			allErrs = append(allErrs, field.Duplicate(path.Child("name"), secret.Name))
+			continue
+		}
+		seen[secret.Name] = true
+		if _, err := mergeConfigData(secret.Name, secret.StringData, secret.Files); err != nil {
+			allErrs = append(allErrs, field.Invalid(path, field.OmitValueType{}, err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateIntOrPercent 校验整数或百分比形式的取值：整数不能为负，百分比在 0%-100% 之间；
+// allowZero 为 false 时还要求取值大于 0
+func validateIntOrPercent(name string, value intstr.IntOrString, allowZero bool) error {
+	if value.Type == intstr.String {
+		if !strings.HasSuffix(value.StrVal, "%") {
+			return fmt.Errorf("%s %q 必须是整数或百分比", name, value.StrVal)
+		}
+		percent, err := intstr.GetScaledValueFromIntOrPercent(&value, 100, true)
+		if err != nil || percent < 0 || percent > 100 {
+			return fmt.Errorf("%s %q 必须在 0%% 到 100%% 之间", name, value.StrVal)
+		}
+		if !allowZero && percent == 0 {
+			return fmt.Errorf("%s 不能为 0%%", name)
+		}
+		return nil
+	}
+	if value.IntVal < 0 {
+		return fmt.Errorf("%s 不能为负数，当前为 %d", name, value.IntVal)
+	}
+	if !allowZero && value.IntVal == 0 {
+		return fmt.Errorf("%s 必须大于 0", name)
+	}
+	return nil
+}
+
+// validateCronJob 校验 CronJob 必须配置 schedule：支持 @daily 之类的宏或标准的 5 段 cron 表达式，
+// 时区只能通过 timeZone 指定（API server 拒绝 schedule 中的 TZ= / CRON_TZ= 前缀）
+func validateCronJob(cron *appsv1alpha1.CronJobSpec, path *field.Path) field.ErrorList {
+	if cron == nil {
+		return field.ErrorList{field.Required(path, "workloadType 为 CronJob 时必须配置 cronJob")}
+	}
+	schedulePath := path.Child("schedule")
+	schedule := strings.TrimSpace(cron.Schedule)
+	switch {
+	case schedule == "":
+		return field.ErrorList{field.Required(schedulePath, "CronJob 的 schedule 不能为空")}
+	case strings.Contains(schedule, "TZ="):
+		return field.ErrorList{field.Invalid(schedulePath, cron.Schedule, "请使用 timeZone 字段指定时区")}
+	case strings.HasPrefix(schedule, "@"):
+		return nil
+	case len(strings.Fields(schedule)) != 5:
+		return field.ErrorList{field.Invalid(schedulePath, cron.Schedule, "schedule 必须是 5 段 cron 表达式或 @ 开头的宏")}
+	}
+	return nil
+}
+
+// ValidateKubeAppUpdate 校验更新：Service 的 headless（clusterIP）以及 StatefulSet 的 serviceName、
+// podManagementPolicy 和 volumeClaimTemplates 创建后由 API server 禁止修改，在准入阶段提前拒绝，避免 reconcile 反复失败
+func ValidateKubeAppUpdate(oldApp, newApp *appsv1alpha1.KubeApp) field.ErrorList {
+	allErrs := validateServiceUpdate(oldApp, newApp)
+	return append(allErrs, validateStatefulSetUpdate(oldApp, newApp)...)
+}
+
+// validateServiceUpdate 禁止已有 Service 切换 headless：clusterIP 创建后不可修改
+func validateServiceUpdate(oldApp, newApp *appsv1alpha1.KubeApp) field.ErrorList {
+	if !oldApp.Spec.EnableService || !newApp.Spec.EnableService {
+		return nil
+	}
+	headless := map[string]bool{}
+	if oldApp.Spec.Service != nil {
+		headless[oldApp.Spec.Service.Name] = oldApp.Spec.Service.Headless
+	}
+	for _, svc := range oldApp.Spec.ExtraServices {
+		headless[svc.Name] = svc.Headless
+	}
+
+	var allErrs field.ErrorList
+	check := func(svc *appsv1alpha1.ServiceSpec, path *field.Path) {
+		if was, ok := headless[svc.Name]; ok && was != svc.Headless {
+			allErrs = append(allErrs, field.Forbidden(path.Child("headless"), "Service 的 clusterIP 创建后不可修改，请使用新的 Service 名称"))
+		}
+	}
+	if newApp.Spec.Service != nil {
+		check(newApp.Spec.Service, field.NewPath("spec", "service"))
+	}
+	for i := range newApp.Spec.ExtraServices {
+		check(&newApp.Spec.ExtraServices[i], field.NewPath("spec", "extraServices").Index(i))
+	}
+	return allErrs
+}
+
+// validateStatefulSetUpdate 禁止修改 StatefulSet 创建后不可变的字段
+func validateStatefulSetUpdate(oldApp, newApp *appsv1alpha1.KubeApp) field.ErrorList {
+	if oldApp.Spec.EffectiveWorkloadType() != appsv1alpha1.WorkloadStatefulSet ||
+		newApp.Spec.EffectiveWorkloadType() != appsv1alpha1.WorkloadStatefulSet ||
+		DeploymentName(oldApp) != DeploymentName(newApp) {
+		return nil
+	}
+
+	var allErrs field.ErrorList
+	path := field.NewPath("spec", "statefulSet")
+	oldSts, newSts := oldApp.Spec.StatefulSet, newApp.Spec.StatefulSet
+	if oldSts == nil {
+		oldSts = &appsv1alpha1.StatefulSetSpec{}
+	}
+	if newSts == nil {
+		newSts = &appsv1alpha1.StatefulSetSpec{}
+	}
+
+	if HeadlessServiceName(oldApp) != HeadlessServiceName(newApp) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("serviceName"), "StatefulSet 创建后不可修改"))
+	}
+	if podManagementPolicy(oldSts) != podManagementPolicy(newSts) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("podManagementPolicy"), "StatefulSet 创建后不可修改"))
+	}
+	if !equality.Semantic.DeepEqual(oldSts.VolumeClaimTemplates, newSts.VolumeClaimTemplates) {
+		allErrs = append(allErrs, field.Forbidden(path.Child("volumeClaimTemplates"), "StatefulSet 创建后不可修改"))
+	}
+	return allErrs
+}
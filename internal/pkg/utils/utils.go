@@ -4,6 +4,7 @@ import (
     "context"
     "fmt"
     "k8s.io/apimachinery/pkg/api/errors"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
     "k8s.io/apimachinery/pkg/util/intstr"
     "sigs.k8s.io/controller-runtime/pkg/client"
     logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -73,6 +74,30 @@ func DeleteIfExists(ctx context.Context, cli client.Client, obj client.Object) e
     return cli.Delete(ctx, obj)
 }
 
+// DeleteIfControlled 只删除 controller ownerReference 指向 owner 的资源，
+// 不存在或属于其他控制器（或手工创建）的同名资源都跳过，避免误删
+
+func DeleteIfControlled(ctx context.Context, cli client.Client, obj client.Object, owner metav1.Object) error {
+    logger := logf.FromContext(ctx)
+
+    err := cli.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+    if err != nil {
+        if errors.IsNotFound(err) {
+            logger.V(1).Info("资源不存在，跳过删除", "Kind", obj.GetObjectKind().GroupVersionKind().Kind, "Name", obj.GetName())
+            return nil
+        }
+        return err
+    }
+
+    if !metav1.IsControlledBy(obj, owner) {
+        logger.Info("资源不归属当前对象，跳过删除", "Kind", obj.GetObjectKind().GroupVersionKind().Kind, "Name", obj.GetName(), "Owner", owner.GetName())
+        return nil
+    }
+
+    logger.Info("删除资源", "Kind", obj.GetObjectKind().GroupVersionKind().Kind, "Name", obj.GetName())
+    return cli.Delete(ctx, obj)
+}
+
 
 func intstrFromInt(i int) intstr.IntOrString {
     return intstr.FromInt(i)
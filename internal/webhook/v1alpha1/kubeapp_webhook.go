@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	custom "github.com/k8s/kube-app-operator/internal/custom"
+)
+
+// log is for logging in this package.
+var kubeapplog = logf.Log.WithName("kubeapp-resource")
+
+// SetupKubeAppWebhookWithManager registers the webhook for KubeApp in the manager.
+func SetupKubeAppWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&appsv1alpha1.KubeApp{}).
+		WithValidator(&KubeAppCustomValidator{}).
+		WithDefaulter(&KubeAppCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-kube-com-v1alpha1-kubeapp,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps.kube.com,resources=kubeapps,verbs=create;update,versions=v1alpha1,name=mkubeapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// KubeAppCustomDefaulter writes the defaults the controller would otherwise apply implicitly
+// (replicas, Service type, ingress path and pathType) into the stored object, so that
+// `kubectl get -o yaml`, the controller, the REST API and the templates agree on the effective spec.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as it is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type KubeAppCustomDefaulter struct{}
+
+var _ webhook.CustomDefaulter = &KubeAppCustomDefaulter{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the Kind KubeApp.
+func (d *KubeAppCustomDefaulter) Default(_ context.Context, obj runtime.Object) error {
+	kubeapp, ok := obj.(*appsv1alpha1.KubeApp)
+	if !ok {
+		return fmt.Errorf("expected an KubeApp object but got %T", obj)
+	}
+	kubeapplog.Info("Defaulting for KubeApp", "name", kubeapp.GetName())
+
+	custom.DefaultKubeApp(kubeapp)
+	return nil
+}
+
+// NOTE: The 'path' attribute must follow a specific pattern and should not be modified directly here.
+// Modifying the path for an invalid path can cause API server errors; failing to locate the webhook.
+// +kubebuilder:webhook:path=/validate-apps-kube-com-v1alpha1-kubeapp,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.kube.com,resources=kubeapps,verbs=create;update,versions=v1alpha1,name=vkubeapp-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// KubeAppCustomValidator rejects KubeApps whose spec the reconciler could never apply,
+// so the error surfaces at `kubectl apply` time instead of in a reconcile loop.
+//
+// NOTE: The +kubebuilder:object:generate=false marker prevents controller-gen from generating DeepCopy methods,
+// as this struct is used only for temporary operations and does not need to be deeply copied.
+// +kubebuilder:object:generate=false
+type KubeAppCustomValidator struct{}
+
+var _ webhook.CustomValidator = &KubeAppCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type KubeApp.
+func (v *KubeAppCustomValidator) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	kubeapp, ok := obj.(*appsv1alpha1.KubeApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a KubeApp object but got %T", obj)
+	}
+	kubeapplog.Info("Validation for KubeApp upon creation", "name", kubeapp.GetName())
+
+	return nil, validateKubeApp(nil, kubeapp)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type KubeApp.
+func (v *KubeAppCustomValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	kubeapp, ok := newObj.(*appsv1alpha1.KubeApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a KubeApp object for the newObj but got %T", newObj)
+	}
+	oldKubeapp, ok := oldObj.(*appsv1alpha1.KubeApp)
+	if !ok {
+		return nil, fmt.Errorf("expected a KubeApp object for the oldObj but got %T", oldObj)
+	}
+	kubeapplog.Info("Validation for KubeApp upon update", "name", kubeapp.GetName())
+
+	// 删除中的 KubeApp 只会被移除 finalizer，不能因为历史遗留的非法 spec 卡住删除
+	if !kubeapp.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return nil, validateKubeApp(oldKubeapp, kubeapp)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type KubeApp.
+func (v *KubeAppCustomValidator) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateKubeApp 把字段错误列表包装成 API server 能识别的 Invalid 错误，oldKubeapp 为 nil 表示创建
+func validateKubeApp(oldKubeapp, kubeapp *appsv1alpha1.KubeApp) error {
+	allErrs := custom.ValidateKubeApp(kubeapp)
+	if oldKubeapp != nil {
+		allErrs = append(allErrs, custom.ValidateKubeAppUpdate(oldKubeapp, kubeapp)...)
+	}
+	if len(allErrs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(appsv1alpha1.GroupVersion.WithKind("KubeApp").GroupKind(), kubeapp.Name, allErrs)
+}
@@ -0,0 +1,519 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+)
+
+var _ = Describe("KubeApp Webhook", func() {
+	var (
+		obj       *appsv1alpha1.KubeApp
+		oldObj    *appsv1alpha1.KubeApp
+		validator KubeAppCustomValidator
+		defaulter KubeAppCustomDefaulter
+	)
+
+	BeforeEach(func() {
+		replicas := int32(1)
+		obj = &appsv1alpha1.KubeApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec: appsv1alpha1.KubeAppSpec{
+				EnableDeployment: true,
+				EnableService:    true,
+				EnableIngress:    true,
+				Deployment: &appsv1alpha1.DeploymentSpec{
+					Name:     "web",
+					Image:    "nginx:1.27",
+					Replicas: &replicas,
+					Volumes: []appsv1alpha1.VolumeConfig{
+						{Name: "cache", EmptyDir: &corev1.EmptyDirVolumeSource{}},
+					},
+					VolumeMounts: []appsv1alpha1.VolumeMount{
+						{Name: "cache", MountPath: "/cache"},
+					},
+				},
+				Service: &appsv1alpha1.ServiceSpec{Name: "web", Port: 80, TargetPort: 8080},
+				Ingress: &appsv1alpha1.IngressSpec{
+					Host:        "web.example.com",
+					ServiceName: "web",
+					ServicePort: 80,
+				},
+			},
+		}
+		oldObj = obj.DeepCopy()
+		validator = KubeAppCustomValidator{}
+		defaulter = KubeAppCustomDefaulter{}
+	})
+
+	// causeFields 返回 Invalid 错误中所有出错的字段路径
+	causeFields := func(err error) []string {
+		Expect(apierrors.IsInvalid(err)).To(BeTrue())
+		var fields []string
+		for _, cause := range err.(apierrors.APIStatus).Status().Details.Causes {
+			fields = append(fields, cause.Field)
+		}
+		return fields
+	}
+
+	Context("When creating KubeApp under Defaulting Webhook", func() {
+		It("Should write the effective defaults into the spec", func() {
+			obj.Spec.Deployment.Replicas = nil
+			obj.Spec.Service.Type = ""
+			obj.Spec.Ingress.Path = ""
+			obj.Spec.Ingress.PathType = ""
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.Deployment.Replicas).To(Equal(int32(1)))
+			Expect(obj.Spec.WorkloadType).To(Equal(appsv1alpha1.WorkloadDeployment))
+			Expect(obj.Spec.Service.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(obj.Spec.Ingress.Path).To(Equal("/"))
+			Expect(obj.Spec.Ingress.PathType).To(Equal(networkingv1.PathTypePrefix))
+		})
+
+		It("Should default autoscaling.minReplicas to deployment.replicas", func() {
+			replicas := int32(3)
+			obj.Spec.Deployment.Replicas = &replicas
+			obj.Spec.Autoscaling = &appsv1alpha1.AutoscalingSpec{MaxReplicas: 10}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.Autoscaling.MinReplicas).To(Equal(int32(3)))
+		})
+
+		It("Should default the rollout strategy to a 25% RollingUpdate", func() {
+			obj.Spec.Deployment.Strategy = &appsv1alpha1.DeploymentStrategySpec{}
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.Deployment.Strategy.Type).To(Equal(appsv1.RollingUpdateDeploymentStrategyType))
+			Expect(*obj.Spec.Deployment.Strategy.MaxSurge).To(Equal(intstr.FromString("25%")))
+			Expect(*obj.Spec.Deployment.Strategy.MaxUnavailable).To(Equal(intstr.FromString("25%")))
+		})
+
+		It("Should keep values that are already set", func() {
+			replicas := int32(4)
+			obj.Spec.Deployment.Replicas = &replicas
+			obj.Spec.Service.Type = corev1.ServiceTypeNodePort
+			obj.Spec.Ingress.Path = "/api"
+			obj.Spec.Ingress.PathType = networkingv1.PathTypeExact
+
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(*obj.Spec.Deployment.Replicas).To(Equal(int32(4)))
+			Expect(obj.Spec.Service.Type).To(Equal(corev1.ServiceTypeNodePort))
+			Expect(obj.Spec.Ingress.Path).To(Equal("/api"))
+			Expect(obj.Spec.Ingress.PathType).To(Equal(networkingv1.PathTypeExact))
+		})
+	})
+
+	Context("When creating or updating KubeApp under Validating Webhook", func() {
+		It("Should admit a consistent spec", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if the image is missing", func() {
+			obj.Spec.Deployment.Image = ""
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment"))
+		})
+
+		It("Should deny a volumeMount that names an undeclared volume", func() {
+			obj.Spec.Deployment.VolumeMounts = append(obj.Spec.Deployment.VolumeMounts,
+				appsv1alpha1.VolumeMount{Name: "data", MountPath: "/data"})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.volumeMounts[1].name"))
+		})
+
+		It("Should admit init containers and native sidecars that mount declared volumes", func() {
+			always := corev1.ContainerRestartPolicyAlways
+			obj.Spec.Deployment.InitContainers = []appsv1alpha1.ContainerSpec{{Name: "migrate", Image: "migrate:1"}}
+			obj.Spec.Deployment.Sidecars = []appsv1alpha1.ContainerSpec{{
+				Name:          "proxy",
+				Image:         "envoy:1.30",
+				RestartPolicy: &always,
+				StartupProbe:  &corev1.Probe{},
+				VolumeMounts:  []appsv1alpha1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny duplicate container names, unknown mounts and probes on plain init containers", func() {
+			obj.Spec.Deployment.InitContainers = []appsv1alpha1.ContainerSpec{{
+				Name:          "migrate",
+				Image:         "migrate:1",
+				LivenessProbe: &corev1.Probe{},
+				VolumeMounts:  []appsv1alpha1.VolumeMount{{Name: "data", MountPath: "/data"}},
+			}}
+			obj.Spec.Deployment.Sidecars = []appsv1alpha1.ContainerSpec{{Name: "web", Image: "fluent-bit:3"}}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf(
+				"spec.deployment.initContainers[0].volumeMounts[0].name",
+				"spec.deployment.initContainers[0].livenessProbe",
+				"spec.deployment.sidecars[0].name",
+			))
+		})
+
+		It("Should let a StatefulSet mount its volumeClaimTemplates", func() {
+			obj.Spec.WorkloadType = appsv1alpha1.WorkloadStatefulSet
+			obj.Spec.StatefulSet = &appsv1alpha1.StatefulSetSpec{
+				VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplate{{Name: "data", Storage: "1Gi"}},
+			}
+			obj.Spec.Deployment.VolumeMounts = append(obj.Spec.Deployment.VolumeMounts,
+				appsv1alpha1.VolumeMount{Name: "data", MountPath: "/data"})
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.StatefulSet.VolumeClaimTemplates[0].Storage = "lots"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.statefulSet.volumeClaimTemplates[0]"))
+		})
+
+		It("Should deny changing the volumeClaimTemplates of an existing StatefulSet", func() {
+			oldObj.Spec.WorkloadType = appsv1alpha1.WorkloadStatefulSet
+			oldObj.Spec.StatefulSet = &appsv1alpha1.StatefulSetSpec{
+				VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplate{{Name: "data", Storage: "1Gi"}},
+			}
+			obj = oldObj.DeepCopy()
+			obj.Spec.StatefulSet.VolumeClaimTemplates[0].Storage = "2Gi"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.statefulSet.volumeClaimTemplates"))
+		})
+
+		It("Should only accept maxUnavailable for a RollingUpdate DaemonSet", func() {
+			obj.Spec.WorkloadType = appsv1alpha1.WorkloadDaemonSet
+			maxUnavailable := intstr.FromString("25%")
+			obj.Spec.DaemonSet = &appsv1alpha1.DaemonSetSpec{MaxUnavailable: &maxUnavailable}
+			obj.Spec.Deployment.Tolerations = []corev1.Toleration{{Key: "node-role.kubernetes.io/control-plane", Operator: corev1.TolerationOpExists}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.DaemonSet.UpdateStrategy = appsv1.OnDeleteDaemonSetStrategyType
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.daemonSet.maxUnavailable"))
+
+			obj.Spec.DaemonSet.UpdateStrategy = appsv1.RollingUpdateDaemonSetStrategyType
+			maxUnavailable = intstr.FromString("150%")
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.daemonSet.maxUnavailable"))
+		})
+
+		It("Should validate the Deployment rollout strategy", func() {
+			maxSurge, maxUnavailable := intstr.FromInt32(1), intstr.FromString("0%")
+			obj.Spec.Deployment.Strategy = &appsv1alpha1.DeploymentStrategySpec{MaxSurge: &maxSurge, MaxUnavailable: &maxUnavailable}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			maxSurge = intstr.FromInt32(0)
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.strategy"))
+
+			obj.Spec.Deployment.Strategy.Type = appsv1.RecreateDeploymentStrategyType
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.strategy"))
+
+			progressDeadline := int32(30)
+			obj.Spec.Deployment.Strategy = &appsv1alpha1.DeploymentStrategySpec{
+				Type:                    appsv1.RecreateDeploymentStrategyType,
+				MinReadySeconds:         30,
+				ProgressDeadlineSeconds: &progressDeadline,
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.strategy"))
+
+			progressDeadline = 60
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should validate the securityProfile against its Pod Security level", func() {
+			obj.Spec.Deployment.SecurityProfile = &appsv1alpha1.SecurityProfileSpec{Type: appsv1alpha1.SecurityProfileRestricted}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Deployment.Volumes = append(obj.Spec.Deployment.Volumes, appsv1alpha1.VolumeConfig{
+				Name:     "logs",
+				HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"},
+			})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.securityProfile"))
+
+			obj.Spec.Deployment.Volumes = obj.Spec.Deployment.Volumes[:1]
+			obj.Spec.Deployment.SecurityProfile = &appsv1alpha1.SecurityProfileSpec{
+				Type:               appsv1alpha1.SecurityProfileBaseline,
+				PodSecurityContext: &corev1.PodSecurityContext{},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.securityProfile"))
+
+			obj.Spec.Deployment.SecurityProfile = &appsv1alpha1.SecurityProfileSpec{Type: appsv1alpha1.SecurityProfileCustom}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.deployment.securityProfile"))
+
+			privileged := true
+			obj.Spec.Deployment.SecurityProfile.ContainerSecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should only accept paused in the pause annotations", func() {
+			obj.Annotations = map[string]string{
+				appsv1alpha1.ReconcileAnnotation: appsv1alpha1.PausedValue,
+				appsv1alpha1.RolloutAnnotation:   appsv1alpha1.PausedValue,
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Annotations[appsv1alpha1.ReconcileAnnotation] = "pause"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("metadata.annotations[kubeapp.io/reconcile]"))
+
+			delete(obj.Annotations, appsv1alpha1.ReconcileAnnotation)
+			obj.Spec.WorkloadType = appsv1alpha1.WorkloadDaemonSet
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("metadata.annotations[kubeapp.io/rollout]"))
+		})
+
+		It("Should only allow autoscaling with a sane replica range on Deployments and StatefulSets", func() {
+			minReplicas := int32(4)
+			obj.Spec.Autoscaling = &appsv1alpha1.AutoscalingSpec{MinReplicas: &minReplicas, MaxReplicas: 8}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Autoscaling.MaxReplicas = 2
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.autoscaling.maxReplicas"))
+
+			obj.Spec.Autoscaling.MaxReplicas = 8
+			obj.Spec.WorkloadType = appsv1alpha1.WorkloadDaemonSet
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.autoscaling"))
+		})
+
+		It("Should require exactly one of minAvailable and maxUnavailable in the disruptionBudget", func() {
+			minAvailable := intstr.FromString("50%")
+			maxUnavailable := intstr.FromInt32(1)
+			obj.Spec.DisruptionBudget = &appsv1alpha1.DisruptionBudgetSpec{MinAvailable: &minAvailable}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.DisruptionBudget.MaxUnavailable = &maxUnavailable
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.disruptionBudget"))
+
+			obj.Spec.DisruptionBudget = &appsv1alpha1.DisruptionBudgetSpec{MaxUnavailable: &maxUnavailable}
+			obj.Spec.WorkloadType = appsv1alpha1.WorkloadCronJob
+			obj.Spec.CronJob = &appsv1alpha1.CronJobSpec{Schedule: "@daily"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.disruptionBudget"))
+		})
+
+		It("Should validate named ports and advanced settings of the Services", func() {
+			obj.Spec.Service.Ports = []appsv1alpha1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: 8080},
+				{Name: "grpc", Port: 9000, TargetPort: 9000},
+			}
+			obj.Spec.Service.Type = corev1.ServiceTypeLoadBalancer
+			obj.Spec.Service.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyLocal
+			obj.Spec.Service.LoadBalancerSourceRanges = []string{"10.0.0.0/8"}
+			obj.Spec.ExtraServices = []appsv1alpha1.ServiceSpec{{
+				Name:     "web-metrics",
+				Headless: true,
+				Ports:    []appsv1alpha1.ServicePort{{Name: "metrics", Port: 9090, TargetPort: 9090}},
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Service.Ports[1].Name = "http"
+			obj.Spec.Service.LoadBalancerSourceRanges = []string{"10.0.0.0"}
+			obj.Spec.ExtraServices = append(obj.Spec.ExtraServices, appsv1alpha1.ServiceSpec{Name: "web-metrics", Port: 9091, TargetPort: 9091})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.service", "spec.extraServices[1].name"))
+
+			By("switching an existing Service to headless")
+			obj = oldObj.DeepCopy()
+			obj.Spec.Service.Headless = true
+			_, err = validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.service.headless"))
+		})
+
+		It("Should validate ingress rules, backends and TLS hosts", func() {
+			obj.Spec.Ingress.Rules = []appsv1alpha1.IngressRule{
+				{Host: "web.example.com", Paths: []appsv1alpha1.IngressPath{
+					{Path: "/api", ServiceName: "web", ServicePort: 80},
+					{Path: "/static", ServiceName: "static-files", ServicePort: 8080},
+				}},
+				{Host: "*.example.org", Paths: []appsv1alpha1.IngressPath{{Path: "/", ServiceName: "web", ServicePort: 80}}},
+			}
+			obj.Spec.Ingress.TLS = []appsv1alpha1.IngressTLS{{Hosts: []string{"web.example.com"}, SecretName: "web-tls"}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Ingress.Rules[0].Paths[0].ServicePort = 8080
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.ingress.rules[0].paths[0].servicePort"))
+
+			obj.Spec.Ingress.Rules[0].Paths[0].ServicePort = 80
+			obj.Spec.Ingress.TLS[0].Hosts = []string{"web_example"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.ingress"))
+		})
+
+		It("Should validate the Gateway HTTPRoute and its backends", func() {
+			weight := int32(90)
+			obj.Spec.Routing = &appsv1alpha1.RoutingSpec{Gateway: &appsv1alpha1.GatewayRouteSpec{
+				ParentRefs: []appsv1alpha1.GatewayParentRef{{Name: "public", Namespace: "gateways"}},
+				Hostnames:  []string{"web.example.com", "*.example.org"},
+				Rules: []appsv1alpha1.HTTPRouteRule{{
+					Matches: []appsv1alpha1.HTTPRouteMatch{{Path: "/api", Headers: []appsv1alpha1.HTTPHeaderMatch{{Name: "X-Canary", Value: "true"}}}},
+					BackendRefs: []appsv1alpha1.HTTPBackendRef{
+						{Name: "web", Port: 80, Weight: &weight},
+						{Name: "web-canary", Port: 8080},
+					},
+				}, {}},
+			}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Routing.Gateway.Rules[0].BackendRefs[0].Port = 8080
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.routing.gateway.rules[0].backendRefs[0].port"))
+
+			obj.Spec.Routing.Gateway.Rules[0].BackendRefs[0].Port = 80
+			obj.Spec.Routing.Gateway.Rules[0].Matches[0].Headers[0].Name = "X Canary"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.routing.gateway"))
+
+			obj.Spec.Routing.Gateway.Rules[0].Matches[0].Headers[0].Name = "X-Canary"
+			obj.Spec.Routing.Gateway.ParentRefs = nil
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.routing.gateway"))
+		})
+
+		It("Should validate the networkPolicy peers", func() {
+			obj.Spec.NetworkPolicy = &appsv1alpha1.NetworkPolicySpec{
+				IngressFrom: &appsv1alpha1.NetworkPolicyIngress{
+					Namespaces:        []string{"monitoring"},
+					KubeApps:          []appsv1alpha1.KubeAppRef{{Name: "frontend"}},
+					IngressController: &appsv1alpha1.IngressControllerRef{PodLabels: map[string]string{"app.kubernetes.io/name": "ingress-nginx"}},
+				},
+				EgressTo: &appsv1alpha1.NetworkPolicyEgress{
+					KubeApps: []appsv1alpha1.KubeAppRef{{Name: "db", Namespace: "data"}},
+					CIDRs:    []networkingv1.IPBlock{{CIDR: "10.0.0.0/8", Except: []string{"10.1.0.0/16"}}},
+					DNS:      true,
+				},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.NetworkPolicy.EgressTo.CIDRs[0].Except = []string{"192.168.0.0/24"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.networkPolicy"))
+
+			obj.Spec.NetworkPolicy.EgressTo.CIDRs[0].Except = nil
+			obj.Spec.NetworkPolicy.IngressFrom.Namespaces = []string{"Monitoring"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.networkPolicy"))
+
+			obj.Spec.NetworkPolicy.IngressFrom.Namespaces = nil
+			obj.Spec.EnableDeployment = false
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ContainElement("spec.deployment"))
+		})
+
+		It("Should default the ingress controller namespace of the networkPolicy", func() {
+			obj.Spec.NetworkPolicy = &appsv1alpha1.NetworkPolicySpec{
+				IngressFrom: &appsv1alpha1.NetworkPolicyIngress{IngressController: &appsv1alpha1.IngressControllerRef{}},
+			}
+			Expect(defaulter.Default(ctx, obj)).To(Succeed())
+			Expect(obj.Spec.NetworkPolicy.IngressFrom.IngressController.Namespace).To(Equal("ingress-nginx"))
+		})
+
+		It("Should only accept namespaced rules for the ServiceAccount Role", func() {
+			obj.Spec.ServiceAccount = &appsv1alpha1.ServiceAccountSpec{
+				Name: "web-runner",
+				Rules: []rbacv1.PolicyRule{{
+					APIGroups: []string{""},
+					Resources: []string{"configmaps"},
+					Verbs:     []string{"get"},
+				}},
+			}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.ServiceAccount.Rules = append(obj.Spec.ServiceAccount.Rules, rbacv1.PolicyRule{
+				NonResourceURLs: []string{"/metrics"},
+				Verbs:           []string{"get"},
+			})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.serviceAccount"))
+
+			obj.Spec.ServiceAccount.Rules = nil
+			obj.Spec.ServiceAccount.Name = "Web_Runner"
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.serviceAccount"))
+		})
+
+		It("Should deny duplicate names and keys in configMaps and secrets", func() {
+			obj.Spec.ConfigMaps = []appsv1alpha1.ConfigMapSpec{{
+				Name:  "web-config",
+				Data:  map[string]string{"LOG_LEVEL": "info"},
+				Files: []appsv1alpha1.ConfigFile{{Name: "app.yaml", Content: "port: 8080"}},
+			}}
+			obj.Spec.Secrets = []appsv1alpha1.SecretSpec{{Name: "web-secret", StringData: map[string]string{"password": "s3cret"}}}
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.ConfigMaps[0].Files = append(obj.Spec.ConfigMaps[0].Files, appsv1alpha1.ConfigFile{Name: "LOG_LEVEL", Content: "debug"})
+			obj.Spec.Secrets = append(obj.Spec.Secrets, appsv1alpha1.SecretSpec{Name: "web-secret"})
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.configMaps[0]", "spec.secrets[1].name"))
+			Expect(err.Error()).NotTo(ContainSubstring("s3cret"))
+		})
+
+		It("Should require a valid schedule for a CronJob", func() {
+			obj.Spec.WorkloadType = appsv1alpha1.WorkloadCronJob
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.cronJob"))
+
+			obj.Spec.CronJob = &appsv1alpha1.CronJobSpec{Schedule: "*/5 * * *"}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.cronJob.schedule"))
+
+			obj.Spec.CronJob.Schedule = "@hourly"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny an ingress servicePort that differs from the Service port", func() {
+			obj.Spec.Ingress.ServicePort = 8080
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.ingress.service_port"))
+		})
+
+		It("Should deny an invalid ingressClassName", func() {
+			obj.Spec.Ingress.IngressClassName = "Nginx_Class"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.ingress.ingressClassName"))
+		})
+
+		It("Should require the blocks that are enabled", func() {
+			obj.Spec.EnablePvc = true
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(causeFields(err)).To(ConsistOf("spec.pvc"))
+		})
+
+		It("Should not block a KubeApp that is being deleted", func() {
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			obj.Spec.Deployment.Image = ""
+			Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
+		})
+	})
+})
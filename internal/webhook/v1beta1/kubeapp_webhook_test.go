@@ -0,0 +1,297 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	appsv1alpha1 "github.com/k8s/kube-app-operator/api/v1alpha1"
+	appsv1beta1 "github.com/k8s/kube-app-operator/api/v1beta1"
+)
+
+var _ = Describe("KubeApp Webhook", func() {
+	var (
+		alpha *appsv1alpha1.KubeApp
+		beta  *appsv1beta1.KubeApp
+	)
+
+	BeforeEach(func() {
+		replicas := int32(2)
+		backoffLimit := int32(3)
+		runAsUser := int64(1000)
+		cpuTarget := int32(70)
+		minAvailable := intstr.FromString("50%")
+		rps := resource.MustParse("100")
+		alpha = &appsv1alpha1.KubeApp{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", Labels: map[string]string{"team": "a"}},
+			Spec: appsv1alpha1.KubeAppSpec{
+				EnableDeployment: true,
+				EnableService:    true,
+				EnableIngress:    true,
+				Deployment: &appsv1alpha1.DeploymentSpec{
+					Name:         "web",
+					Image:        "nginx:1.27",
+					Replicas:     &replicas,
+					Ports:        []corev1.ContainerPort{{Name: "http", ContainerPort: 80}},
+					Volumes:      []appsv1alpha1.VolumeConfig{{Name: "cache", EmptyDir: &corev1.EmptyDirVolumeSource{}}},
+					VolumeMounts: []appsv1alpha1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+					Tolerations:  []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpEqual, Value: "web", Effect: corev1.TaintEffectNoSchedule}},
+					InitContainers: []appsv1alpha1.ContainerSpec{{
+						Name:         "migrate",
+						Image:        "migrate:1",
+						Args:         []string{"up"},
+						VolumeMounts: []appsv1alpha1.VolumeMount{{Name: "cache", MountPath: "/cache"}},
+					}},
+					Strategy: &appsv1alpha1.DeploymentStrategySpec{
+						Type:            appsv1.RollingUpdateDeploymentStrategyType,
+						MaxSurge:        &minAvailable,
+						MinReadySeconds: 5,
+					},
+					SecurityProfile: &appsv1alpha1.SecurityProfileSpec{
+						Type:               appsv1alpha1.SecurityProfileCustom,
+						PodSecurityContext: &corev1.PodSecurityContext{RunAsUser: &runAsUser},
+					},
+				},
+				WorkloadType: appsv1alpha1.WorkloadStatefulSet,
+				StatefulSet: &appsv1alpha1.StatefulSetSpec{
+					VolumeClaimTemplates: []appsv1alpha1.VolumeClaimTemplate{{Name: "data", Storage: "1Gi"}},
+				},
+				DaemonSet: &appsv1alpha1.DaemonSetSpec{UpdateStrategy: appsv1.OnDeleteDaemonSetStrategyType},
+				Autoscaling: &appsv1alpha1.AutoscalingSpec{
+					MaxReplicas:                    5,
+					TargetCPUUtilizationPercentage: &cpuTarget,
+					Metrics: []autoscalingv2.MetricSpec{{
+						Type: autoscalingv2.PodsMetricSourceType,
+						Pods: &autoscalingv2.PodsMetricSource{
+							Metric: autoscalingv2.MetricIdentifier{Name: "requests_per_second"},
+							Target: autoscalingv2.MetricTarget{Type: autoscalingv2.AverageValueMetricType, AverageValue: &rps},
+						},
+					}},
+				},
+				DisruptionBudget: &appsv1alpha1.DisruptionBudgetSpec{MinAvailable: &minAvailable},
+				ConfigMaps: []appsv1alpha1.ConfigMapSpec{{
+					Name:  "web-config",
+					Data:  map[string]string{"LOG_LEVEL": "info"},
+					Files: []appsv1alpha1.ConfigFile{{Name: "app.yaml", Content: "port: 8080"}},
+				}},
+				Secrets: []appsv1alpha1.SecretSpec{{Name: "web-secret", Type: corev1.SecretTypeOpaque, StringData: map[string]string{"password": "s3cret"}}},
+				Job:     &appsv1alpha1.JobSpec{BackoffLimit: &backoffLimit, RestartPolicy: corev1.RestartPolicyNever},
+				CronJob: &appsv1alpha1.CronJobSpec{Schedule: "@daily", ConcurrencyPolicy: batchv1.ForbidConcurrent},
+				Service: &appsv1alpha1.ServiceSpec{Name: "web", Port: 80, TargetPort: 8080, Type: corev1.ServiceTypeClusterIP},
+				Ingress: &appsv1alpha1.IngressSpec{
+					Host:        "web.example.com",
+					ServiceName: "web",
+					ServicePort: 80,
+					Path:        "/",
+					PathType:    networkingv1.PathTypePrefix,
+				},
+				ServiceAccount: &appsv1alpha1.ServiceAccountSpec{
+					Name:        "web-runner",
+					Annotations: map[string]string{"iam.gke.io/gcp-service-account": "web@project.iam.gserviceaccount.com"},
+					Rules:       []rbacv1.PolicyRule{{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}}},
+				},
+				NetworkPolicy: &appsv1alpha1.NetworkPolicySpec{
+					IngressFrom: &appsv1alpha1.NetworkPolicyIngress{
+						Namespaces:        []string{"monitoring"},
+						KubeApps:          []appsv1alpha1.KubeAppRef{{Name: "frontend"}},
+						IngressController: &appsv1alpha1.IngressControllerRef{Namespace: "ingress-nginx"},
+					},
+					EgressTo: &appsv1alpha1.NetworkPolicyEgress{
+						KubeApps: []appsv1alpha1.KubeAppRef{{Name: "db", Namespace: "data"}},
+						CIDRs:    []networkingv1.IPBlock{{CIDR: "10.0.0.0/8"}},
+						DNS:      true,
+					},
+				},
+				Routing: &appsv1alpha1.RoutingSpec{Gateway: &appsv1alpha1.GatewayRouteSpec{
+					ParentRefs: []appsv1alpha1.GatewayParentRef{{Name: "public", Namespace: "gateways", SectionName: "https"}},
+					Hostnames:  []string{"web.example.com"},
+					Rules: []appsv1alpha1.HTTPRouteRule{{
+						Matches:     []appsv1alpha1.HTTPRouteMatch{{Path: "/api", Headers: []appsv1alpha1.HTTPHeaderMatch{{Name: "X-Canary", Value: "true"}}}},
+						BackendRefs: []appsv1alpha1.HTTPBackendRef{{Name: "web", Port: 80, Weight: &replicas}},
+					}},
+				}},
+			},
+			Status: appsv1alpha1.KubeAppStatus{
+				ObservedGeneration: 3,
+				CurrentRevision:    4,
+				Deployment:         &appsv1alpha1.DeploymentStatusSummary{Name: "web", Replicas: 2, ReadyReplicas: 1},
+				Job:                &appsv1alpha1.JobStatusSummary{Name: "web", Kind: "CronJob", LastRunResult: "Succeeded"},
+				Autoscaling:        &appsv1alpha1.AutoscalingStatusSummary{Name: "web", MinReplicas: 2, MaxReplicas: 5, CurrentReplicas: 3},
+				HTTPRoute:          &appsv1alpha1.HTTPRouteStatusSummary{Name: "web", AcceptedParents: []string{"gateways/public"}},
+			},
+		}
+
+		beta = &appsv1beta1.KubeApp{}
+		Expect(alpha.ConvertTo(beta)).To(Succeed())
+		// beta stands for an object written by a v1beta1 client: drop the v1alpha1 spec the
+		// up-conversion saved.
+		beta.Annotations = nil
+		always := corev1.ContainerRestartPolicyAlways
+		beta.Spec.Deployment.Sidecars = []appsv1beta1.ContainerSpec{{Name: "log-agent", Image: "fluent-bit:3", RestartPolicy: &always}}
+		beta.Spec.Service.Ports[0].Name = "http"
+		beta.Spec.Service.Ports = append(beta.Spec.Service.Ports, appsv1beta1.ServicePort{Name: "metrics", Port: 9090, TargetPort: 9090})
+		beta.Spec.Ingress.Rules = append(beta.Spec.Ingress.Rules, appsv1beta1.IngressRule{
+			Host:  "api.example.com",
+			Paths: []appsv1beta1.IngressPath{{Path: "/v1", PathType: networkingv1.PathTypePrefix, ServiceName: "web", ServicePort: 80}},
+		})
+		beta.Spec.Ingress.TLS = []appsv1beta1.IngressTLS{{Hosts: []string{"web.example.com", "api.example.com"}, SecretName: "web-tls"}}
+		beta.Spec.Ingress.Annotations = map[string]string{"nginx.ingress.kubernetes.io/proxy-body-size": "8m"}
+	})
+
+	Context("When converting KubeApp under Conversion Webhook", func() {
+		It("Should round-trip v1alpha1 through v1beta1 losslessly", func() {
+			hub := &appsv1beta1.KubeApp{}
+			Expect(alpha.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Spec.Service.Ports).To(Equal([]appsv1beta1.ServicePort{{Port: 80, TargetPort: 8080}}))
+			Expect(hub.Spec.Ingress.Rules).To(HaveLen(1))
+			Expect(hub.Spec.Deployment.InitContainers).To(HaveLen(1))
+			Expect(hub.Spec.Deployment.InitContainers[0].VolumeMounts).To(Equal([]appsv1beta1.VolumeMount{{Name: "cache", MountPath: "/cache"}}))
+
+			back := &appsv1alpha1.KubeApp{}
+			Expect(back.ConvertFrom(hub)).To(Succeed())
+			Expect(back).To(Equal(alpha))
+		})
+
+		It("Should round-trip v1beta1 through v1alpha1 losslessly", func() {
+			spoke := &appsv1alpha1.KubeApp{}
+			Expect(spoke.ConvertFrom(beta)).To(Succeed())
+			Expect(spoke.Spec.Service.Ports).To(Equal([]appsv1alpha1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: 8080},
+				{Name: "metrics", Port: 9090, TargetPort: 9090},
+			}))
+			Expect(spoke.Spec.Ingress.Rules).To(HaveLen(2))
+			Expect(spoke.Spec.Ingress.Rules[1].Host).To(Equal("api.example.com"))
+			Expect(spoke.Spec.Deployment.Sidecars).To(HaveLen(1))
+			Expect(spoke.Annotations).NotTo(HaveKey(appsv1alpha1.ConversionDataAnnotation))
+
+			back := &appsv1beta1.KubeApp{}
+			Expect(spoke.ConvertTo(back)).To(Succeed())
+			Expect(back).To(Equal(beta))
+		})
+
+		It("Should keep a single unnamed v1beta1 port in the ports list", func() {
+			beta.Spec.Service.Ports = []appsv1beta1.ServicePort{{Port: 80, TargetPort: 8080}}
+			spoke := &appsv1alpha1.KubeApp{}
+			Expect(spoke.ConvertFrom(beta)).To(Succeed())
+			Expect(spoke.Spec.Service.Port).To(BeZero())
+			Expect(spoke.Spec.Service.Ports).To(Equal([]appsv1alpha1.ServicePort{{Port: 80, TargetPort: 8080}}))
+			Expect(spoke.Annotations).NotTo(HaveKey(appsv1alpha1.ConversionDataAnnotation))
+
+			back := &appsv1beta1.KubeApp{}
+			Expect(spoke.ConvertTo(back)).To(Succeed())
+			Expect(back).To(Equal(beta))
+		})
+
+		It("Should round-trip every v1alpha1 Service port form", func() {
+			for _, service := range []*appsv1alpha1.ServiceSpec{
+				{Name: "web", Port: 80, TargetPort: 8080},
+				{Name: "web", Ports: []appsv1alpha1.ServicePort{{Port: 80, TargetPort: 8080}}},
+				{Name: "web", Port: 80, Ports: []appsv1alpha1.ServicePort{{Name: "http", Port: 8080, TargetPort: 8080}}},
+			} {
+				alpha.Spec.Service = service
+				hub := &appsv1beta1.KubeApp{}
+				Expect(alpha.ConvertTo(hub)).To(Succeed())
+
+				back := &appsv1alpha1.KubeApp{}
+				Expect(back.ConvertFrom(hub)).To(Succeed())
+				Expect(back).To(Equal(alpha))
+
+				again := &appsv1beta1.KubeApp{}
+				Expect(back.ConvertTo(again)).To(Succeed())
+				Expect(again).To(Equal(hub))
+			}
+		})
+
+		It("Should convert an edited v1alpha1 Service from its fields instead of the saved spec", func() {
+			hub := &appsv1beta1.KubeApp{}
+			Expect(alpha.ConvertTo(hub)).To(Succeed())
+			Expect(hub.Annotations).To(HaveKey(appsv1alpha1.ConversionDataAnnotation))
+			hub.Spec.Service.Ports[0].TargetPort = 8081
+
+			spoke := &appsv1alpha1.KubeApp{}
+			Expect(spoke.ConvertFrom(hub)).To(Succeed())
+			Expect(spoke.Spec.Service.Port).To(BeZero())
+			Expect(spoke.Spec.Service.Ports).To(Equal([]appsv1alpha1.ServicePort{{Port: 80, TargetPort: 8081}}))
+		})
+
+		It("Should keep a single v1beta1 ingress rule in the rules list", func() {
+			path := appsv1beta1.IngressPath{Path: "/", PathType: networkingv1.PathTypePrefix, ServiceName: "web", ServicePort: 80}
+			for _, rules := range [][]appsv1beta1.IngressRule{
+				{{Host: "web.example.com", Paths: []appsv1beta1.IngressPath{path}}},
+				{{Host: "web.example.com"}},
+				{{}},
+			} {
+				beta.Spec.Ingress.Rules = rules
+				spoke := &appsv1alpha1.KubeApp{}
+				Expect(spoke.ConvertFrom(beta)).To(Succeed())
+				Expect(spoke.Spec.Ingress.Host).To(BeEmpty())
+				Expect(spoke.Spec.Ingress.Path).To(BeEmpty())
+				Expect(spoke.Spec.Ingress.Rules).To(HaveLen(1))
+				Expect(spoke.Annotations).NotTo(HaveKey(appsv1alpha1.ConversionDataAnnotation))
+
+				back := &appsv1beta1.KubeApp{}
+				Expect(spoke.ConvertTo(back)).To(Succeed())
+				Expect(back).To(Equal(beta))
+			}
+		})
+
+		It("Should round-trip the v1alpha1 single-route Ingress fields", func() {
+			for _, ingress := range []*appsv1alpha1.IngressSpec{
+				{Host: "web.example.com", Path: "/", PathType: networkingv1.PathTypePrefix, ServiceName: "web", ServicePort: 80},
+				{Host: "web.example.com"},
+				{Rules: []appsv1alpha1.IngressRule{{Host: "web.example.com"}}},
+			} {
+				alpha.Spec.Ingress = ingress
+				hub := &appsv1beta1.KubeApp{}
+				Expect(alpha.ConvertTo(hub)).To(Succeed())
+				Expect(hub.Spec.Ingress.Rules).To(HaveLen(1))
+
+				back := &appsv1alpha1.KubeApp{}
+				Expect(back.ConvertFrom(hub)).To(Succeed())
+				Expect(back).To(Equal(alpha))
+			}
+		})
+
+		It("Should keep v1beta1-only fields when a v1alpha1 client edits the object", func() {
+			spoke := &appsv1alpha1.KubeApp{}
+			Expect(spoke.ConvertFrom(beta)).To(Succeed())
+			spoke.Spec.Deployment.Image = "nginx:1.28"
+			spoke.Spec.Service.Ports[0].TargetPort = 8081
+
+			back := &appsv1beta1.KubeApp{}
+			Expect(spoke.ConvertTo(back)).To(Succeed())
+			Expect(back.Annotations).NotTo(HaveKey(appsv1alpha1.ConversionDataAnnotation))
+			Expect(back.Spec.Deployment.Image).To(Equal("nginx:1.28"))
+			Expect(back.Spec.Deployment.Sidecars).To(Equal(beta.Spec.Deployment.Sidecars))
+			Expect(back.Spec.Service.Ports).To(Equal([]appsv1beta1.ServicePort{
+				{Name: "http", Port: 80, TargetPort: 8081},
+				{Name: "metrics", Port: 9090, TargetPort: 9090},
+			}))
+			Expect(back.Spec.Ingress.Rules).To(Equal(beta.Spec.Ingress.Rules))
+		})
+	})
+})